@@ -28,6 +28,7 @@ func main() {
 
 	app.PreMigrate()
 	app.Start()
+	defer app.Stop()
 	app.PostMigrate()
 
 }
@@ -3,10 +3,14 @@ package applications
 import (
 	"github.com/production-grid/pgrid-core/pkg/config"
 	"github.com/production-grid/pgrid-core/pkg/database/relational"
+	"github.com/production-grid/pgrid-core/pkg/ids"
 	"github.com/production-grid/pgrid-core/pkg/loaders"
 	"github.com/production-grid/pgrid-core/pkg/logging"
 )
 
+// idsSchemaFile holds the snowflake node registry used when leasing node ids.
+const idsSchemaFile = "schema/ids.json"
+
 // Application is the main entry point for productiong grid app.
 // Developers configure the application with services and modules,
 // then starts it.
@@ -16,14 +20,22 @@ type Application struct {
 	SchemaFiles       []string
 	ConfigLoader      loaders.ResourceLoader
 	CoreConfiguration config.CoreConfiguration
+	loggingReady      bool
+	nodeLease         *relational.NodeLease
 }
 
 // Start starts the application.
 func (app *Application) Start() {
 
+	err := app.initLogging()
+
+	if err != nil {
+		app.handleStartupError(err)
+	}
+
 	logging.Infof("Starting %v...", app.Name)
 
-	err := app.initModules()
+	err = app.initModules()
 
 	if err != nil {
 		app.handleStartupError(err)
@@ -35,12 +47,94 @@ func (app *Application) Start() {
 		app.handleStartupError(err)
 	}
 
+	err = app.initIDs()
+
+	if err != nil {
+		app.handleStartupError(err)
+	}
+
+}
+
+// initLogging configures logging from the core configuration. Migrations
+// and Start all call it, so it only runs once.
+func (app *Application) initLogging() error {
+
+	if app.loggingReady {
+		return nil
+	}
+
+	cfg := app.CoreConfiguration.LoggingConfiguration
+
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = app.CoreConfiguration.ApplicationName
+	}
+
+	err := logging.Configure(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	logging.WatchLevelSignals()
+	app.loggingReady = true
+
+	return nil
+
 }
 
 func (app *Application) initDatabase() error {
 	return nil
 }
 
+// initIDs configures the snowflake node id, leasing one from the database
+// if requested.
+func (app *Application) initIDs() error {
+
+	cfg := app.CoreConfiguration.IDConfiguration
+
+	if cfg.NodeID != nil {
+		return ids.ConfigureSnowflake(*cfg.NodeID)
+	}
+
+	if !cfg.LeaseNode {
+		return nil
+	}
+
+	if relational.Primary == nil {
+		err := relational.Init(app.CoreConfiguration.DatabaseConfiguration)
+		if err != nil {
+			return err
+		}
+	}
+
+	lease, err := relational.LeaseSnowflakeNode(cfg.LeaseOwner, cfg.LeaseTTL)
+	if err != nil {
+		return err
+	}
+
+	app.nodeLease = lease
+
+	return nil
+
+}
+
+// Stop releases the resources the application holds, such as its snowflake
+// node lease. Call it on shutdown.
+func (app *Application) Stop() error {
+
+	if app.nodeLease == nil {
+		return nil
+	}
+
+	logging.Infof("Stopping %v...", app.Name)
+
+	err := app.nodeLease.Release()
+	app.nodeLease = nil
+
+	return err
+
+}
+
 func (app *Application) handleStartupError(err error) {
 	logging.Errorln("Application startup failed.")
 	panic(err)
@@ -67,6 +161,10 @@ func (app *Application) initModules() error {
 
 	}
 
+	if app.CoreConfiguration.IDConfiguration.LeaseNode {
+		app.addSchemaFile(idsSchemaFile)
+	}
+
 	return nil
 }
 
@@ -100,9 +198,26 @@ func (app *Application) initModule(mod FeatureModule) error {
 
 }
 
+// addSchemaFile registers a schema file unless it's already registered.
+func (app *Application) addSchemaFile(schemaFile string) {
+
+	for _, existing := range app.SchemaFiles {
+		if existing == schemaFile {
+			return
+		}
+	}
+
+	app.SchemaFiles = append(app.SchemaFiles, schemaFile)
+
+}
+
 // PreMigrate runs the post migration database schema changes, if any.
 func (app *Application) PreMigrate() {
 
+	if err := app.initLogging(); err != nil {
+		app.handleStartupError(err)
+	}
+
 	logging.Infof("Pre migrating Database Schema for %v\n", app.Name)
 
 	err := app.initModules()
@@ -124,6 +239,10 @@ func (app *Application) PreMigrate() {
 // PostMigrate runs the post migration database schema changes, if any.
 func (app *Application) PostMigrate() {
 
+	if err := app.initLogging(); err != nil {
+		app.handleStartupError(err)
+	}
+
 	logging.Infof("Post migrating Database Schema for %v\n", app.Name)
 
 	err := app.initModules()
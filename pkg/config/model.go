@@ -1,16 +1,34 @@
 package config
 
+import (
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/logging"
+)
+
 // CoreConfiguration models the basic configuration of a pgrid application.
 type CoreConfiguration struct {
 	ApplicationName       string                `yaml:"name"`
 	PortNumber            int                   `yaml:"port"`
 	DatabaseConfiguration DatabaseConfiguration `yaml:"database"`
+	LoggingConfiguration  logging.Configuration `yaml:"logging"`
+	IDConfiguration       IDConfiguration       `yaml:"ids"`
+}
+
+// IDConfiguration controls id generation. Snowflake ids need a node id unique
+// to each running process, either set explicitly or leased from the database.
+type IDConfiguration struct {
+	NodeID     *int          `yaml:"nodeId"`
+	LeaseNode  bool          `yaml:"leaseNode"`
+	LeaseOwner string        `yaml:"leaseOwner"`
+	LeaseTTL   time.Duration `yaml:"leaseTTL"`
 }
 
 // DatabaseConfiguration wraps database configuration settings.
 type DatabaseConfiguration struct {
-	Primary RelationalDatasource `yaml:"primary"`
-	Replica RelationalDatasource `yaml:"replica"`
+	Primary            RelationalDatasource `yaml:"primary"`
+	Replica            RelationalDatasource `yaml:"replica"`
+	SlowQueryThreshold time.Duration        `yaml:"slowQueryThreshold"`
 }
 
 // RelationalDatasource describes configuration settings for a relational datasource.
@@ -8,8 +8,8 @@ import (
 	"strings"
 
 	"github.com/production-grid/pgrid-core/pkg/database/schema"
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
 	"github.com/production-grid/pgrid-core/pkg/ids"
-	"github.com/production-grid/pgrid-core/pkg/logging"
 )
 
 // enumerates the constants for database types
@@ -29,6 +29,14 @@ type Entity interface {
 	Delete() error
 }
 
+// IDStrategist is implemented by entities that choose how their ids are
+// generated. It takes precedence over the idgen tag on the ID field, e.g.
+// `col:"id" idgen:"ulid"`. Entities with int64 ID fields map to BIGINT id
+// columns and always use snowflake ids.
+type IDStrategist interface {
+	IDStrategy() ids.Strategy
+}
+
 // ErrNoResults is returned when a finder does not return results.
 var ErrNoResults = errors.New("no results")
 
@@ -38,6 +46,7 @@ type mappingModel struct {
 	TableName       string
 	SoftDeleted     bool
 	IDField         reflect.StructField
+	IDStrategy      ids.Strategy
 	FieldsWithID    []reflect.StructField
 	Fields          []reflect.StructField
 	Type            reflect.Type
@@ -86,7 +95,7 @@ func HardDeleteWithTx(tx *sql.Tx, domain interface{}, table string) error {
 		return err
 	}
 
-	_, err = tx.Exec(model.HardDeleteQuery, id)
+	_, err = sqltrace.Exec(tx, PRIMARY, model.HardDeleteQuery, id)
 
 	return err
 }
@@ -112,9 +121,7 @@ func SoftDelete(domain interface{}, table string) error {
 		return err
 	}
 
-	logging.Traceln("SQL", model.SoftDeleteQuery)
-
-	_, err = Primary.Exec(model.SoftDeleteQuery, id)
+	_, err = sqltrace.Exec(Primary, PRIMARY, model.SoftDeleteQuery, id)
 
 	return err
 }
@@ -140,7 +147,7 @@ func SoftDeleteWithTx(tx *sql.Tx, domain interface{}, table string) error {
 		return err
 	}
 
-	_, err = tx.Exec(model.SoftDeleteQuery, id)
+	_, err = sqltrace.Exec(tx, PRIMARY, model.SoftDeleteQuery, id)
 
 	return err
 }
@@ -154,6 +161,15 @@ func resolveDatabaseType(dbType string) *sql.DB {
 	}
 }
 
+func resolveTargetName(dbType string) string {
+	switch dbType {
+	case REPLICA:
+		return REPLICA
+	default:
+		return PRIMARY
+	}
+}
+
 // FindByID locates an entity by ID
 func FindByID(dbType string, tableName string, id string, target interface{}) error {
 
@@ -163,9 +179,7 @@ func FindByID(dbType string, tableName string, id string, target interface{}) er
 		return err
 	}
 
-	logging.Traceln("SQL:", model.FindByIDQuery, id)
-
-	rows, err := resolveDatabaseType(dbType).Query(model.FindByIDQuery, id)
+	rows, err := sqltrace.Query(resolveDatabaseType(dbType), resolveTargetName(dbType), model.FindByIDQuery, id)
 
 	if err != nil {
 		return err
@@ -184,7 +198,7 @@ func FindByID(dbType string, tableName string, id string, target interface{}) er
 	return ErrNoResults
 }
 
-func scan(model *mappingModel, rows *sql.Rows, target interface{}) error {
+func scan(model *mappingModel, rows *sqltrace.Rows, target interface{}) error {
 
 	targets := make([]interface{}, len(model.FieldsWithID))
 
@@ -216,9 +230,7 @@ func FindByIDWithTx(tx *sql.Tx, tableName string, id string, target interface{})
 		return err
 	}
 
-	logging.Traceln("SQL:", model.FindByIDQuery, id)
-
-	rows, err := tx.Query(model.FindByIDQuery, id)
+	rows, err := sqltrace.Query(tx, PRIMARY, model.FindByIDQuery, id)
 
 	if err != nil {
 		return err
@@ -259,10 +271,13 @@ func SaveWithTx(tx *sql.Tx, domain interface{}, table string) (string, error) {
 	}
 
 	if id == "" {
-		id = ids.NewSecureID()
+		id, err = ids.NewID(resolveIDStrategy(domain, model))
+		if err != nil {
+			return "", err
+		}
 		err = setID(el, model, id)
 		if err != nil {
-			return "", nil
+			return "", err
 		}
 		err = insert(tx, el, model, id)
 	} else {
@@ -273,6 +288,20 @@ func SaveWithTx(tx *sql.Tx, domain interface{}, table string) (string, error) {
 
 }
 
+func resolveIDStrategy(domain interface{}, model *mappingModel) ids.Strategy {
+
+	if isIntegerID(model.IDField) {
+		return ids.StrategySnowflake
+	}
+
+	if strategist, ok := domain.(IDStrategist); ok {
+		return strategist.IDStrategy()
+	}
+
+	return model.IDStrategy
+
+}
+
 func buildUpdateQuery(model *mappingModel) string {
 
 	sb := "update "
@@ -366,9 +395,7 @@ func insert(tx *sql.Tx, el reflect.Value, model *mappingModel, id string) error
 		return err
 	}
 
-	logging.Traceln("SQL:", model.InsertQuery)
-
-	_, err = tx.Exec(model.InsertQuery, params...)
+	_, err = sqltrace.Exec(tx, PRIMARY, model.InsertQuery, params...)
 
 	return err
 }
@@ -381,9 +408,7 @@ func update(tx *sql.Tx, el reflect.Value, model *mappingModel, id string) error
 		return err
 	}
 
-	logging.Traceln("SQL:", model.UpdateQuery)
-
-	_, err = tx.Exec(model.UpdateQuery, params...)
+	_, err = sqltrace.Exec(tx, PRIMARY, model.UpdateQuery, params...)
 
 	return err
 
@@ -392,6 +417,14 @@ func update(tx *sql.Tx, el reflect.Value, model *mappingModel, id string) error
 func resolveID(el reflect.Value, model *mappingModel) (string, error) {
 
 	val := el.FieldByIndex(model.IDField.Index)
+
+	if isIntegerID(model.IDField) {
+		if val.Int() == 0 {
+			return "", nil
+		}
+		return strconv.FormatInt(val.Int(), 10), nil
+	}
+
 	return val.String(), nil
 
 }
@@ -399,12 +432,28 @@ func resolveID(el reflect.Value, model *mappingModel) (string, error) {
 func setID(el reflect.Value, model *mappingModel, id string) error {
 
 	val := el.FieldByIndex(model.IDField.Index)
+
+	if isIntegerID(model.IDField) {
+		intID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		val.SetInt(intID)
+		return nil
+	}
+
 	val.SetString(id)
 
 	return nil
 
 }
 
+// isIntegerID returns true for entities with integer ids, which map to
+// BIGINT id columns.
+func isIntegerID(fld reflect.StructField) bool {
+	return fld.Type.Kind() == reflect.Int64
+}
+
 func resolveModelCache(t reflect.Type) (*mappingModel, error) {
 
 	if modelCache != nil {
@@ -440,9 +489,27 @@ func resolveMappingModel(domain interface{}, table string) (*mappingModel, error
 	}
 
 	model = &mappingModel{
-		TableName: table,
-		Type:      t,
-		IDField:   fld,
+		TableName:  table,
+		Type:       t,
+		IDField:    fld,
+		IDStrategy: ids.Strategy(fld.Tag.Get("idgen")),
+	}
+
+	if !model.IDStrategy.Valid() {
+		return nil, errors.New("unknown id strategy: " + string(model.IDStrategy))
+	}
+
+	switch fld.Type.Kind() {
+	case reflect.String:
+	case reflect.Int64:
+		if model.IDStrategy == "" {
+			model.IDStrategy = ids.StrategySnowflake
+		}
+		if model.IDStrategy != ids.StrategySnowflake {
+			return nil, errors.New("integer ids require the snowflake id strategy")
+		}
+	default:
+		return nil, errors.New("entity ID field must be a string or int64")
 	}
 
 	model.SoftDeleted = schema.IsSoftDeleted(table)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/production-grid/pgrid-core/pkg/config"
 	"github.com/production-grid/pgrid-core/pkg/database/schema"
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
 	"github.com/production-grid/pgrid-core/pkg/loaders"
 	"github.com/production-grid/pgrid-core/pkg/logging"
 
@@ -25,11 +26,15 @@ var (
 	Replica *sql.DB
 )
 
+var logger = logging.For("relational")
+
 // Init sets up the database connections
 func Init(dbconfig config.DatabaseConfiguration) error {
 
 	var err error
 
+	sqltrace.SetSlowThreshold(dbconfig.SlowQueryThreshold)
+
 	if Primary, err = connect(dbconfig.Primary); err != nil {
 		return err
 	}
@@ -90,7 +95,7 @@ func PostMigrate(loader loaders.ResourceLoader, dbconfig config.DatabaseConfigur
 
 // connect opens a configured connection pool to a database.
 func connect(cfg config.RelationalDatasource) (*sql.DB, error) {
-	logging.Infof("Connecting to database: %v", cfg.Schema)
+	logger.Infof("Connecting to database: %v", cfg.Schema)
 
 	connStr := fmt.Sprintf(
 		"host=%v port=%v dbname=%v user=%v password=%v sslmode=disable",
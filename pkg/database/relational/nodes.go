@@ -0,0 +1,237 @@
+package relational
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
+	"github.com/production-grid/pgrid-core/pkg/ids"
+)
+
+const (
+	// DefaultNodeLeaseTTL is how long a snowflake node lease lasts without
+	// renewal. Leases are renewed at a third of the TTL.
+	DefaultNodeLeaseTTL = 5 * time.Minute
+
+	maxNodeLeaseAttempts = 3
+
+	// maxNodeOwnerLength is the size of the id_nodes owner column.
+	maxNodeOwnerLength = 128
+)
+
+// Node lease errors.
+var (
+	ErrNoFreeNode    = errors.New("no free snowflake node ids")
+	ErrNodeLeaseLost = errors.New("snowflake node lease lost")
+)
+
+// NodeLease is a snowflake node id leased from the id_nodes table.
+type NodeLease struct {
+	Node  int
+	Owner string
+
+	ttl      time.Duration
+	expires  time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+/*
+LeaseSnowflakeNode leases a snowflake node id from the id_nodes table, configures
+the default snowflake generator with it and keeps the lease renewed until
+Release is called. An expired lease is reused before a new node id is
+allocated.
+
+The owner labels the lease and defaults to the host name and process id. A
+random instance id is always appended, so processes configured with the same
+owner never share a node id. If the lease is lost, or can't be renewed before
+it expires, the default snowflake generator is disabled and returns
+ErrNodeLeaseLost rather than risk duplicate ids.
+*/
+func LeaseSnowflakeNode(owner string, ttl time.Duration) (*NodeLease, error) {
+
+	if owner == "" {
+		host, _ := os.Hostname()
+		owner = fmt.Sprintf("%v:%v", host, os.Getpid())
+	}
+
+	instance := ids.NewSecureID()
+	if max := maxNodeOwnerLength - len(instance) - 1; len(owner) > max {
+		owner = owner[:max]
+	}
+	owner = owner + "/" + instance
+
+	if ttl <= 0 {
+		ttl = DefaultNodeLeaseTTL
+	}
+
+	var node int
+	var err error
+
+	expires := time.Now().Add(ttl)
+
+	for attempt := 0; attempt < maxNodeLeaseAttempts; attempt++ {
+		node, err = acquireNode(owner, ttl)
+		if err != errNodeTaken {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = ids.ConfigureSnowflake(node)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Leased snowflake node %v for %v", node, owner)
+
+	lease := &NodeLease{Node: node, Owner: owner, ttl: ttl, expires: expires, stop: make(chan struct{})}
+	go lease.renew()
+
+	return lease, nil
+
+}
+
+// errNodeTaken signals that another process claimed the node id first.
+var errNodeTaken = errors.New("snowflake node id taken")
+
+func acquireNode(owner string, ttl time.Duration) (int, error) {
+
+	tx, err := NewWritableTx()
+	if err != nil {
+		return 0, err
+	}
+
+	node, err := acquireNodeWithTx(tx, owner, ttl)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return node, tx.Commit()
+
+}
+
+func acquireNodeWithTx(tx *sql.Tx, owner string, ttl time.Duration) (int, error) {
+
+	now := time.Now().UTC()
+	expires := now.Add(ttl)
+
+	node, found, err := queryNode(tx, "SELECT node_id FROM id_nodes WHERE expires_at < $1 ORDER BY node_id LIMIT 1 FOR UPDATE SKIP LOCKED", now)
+	if err != nil {
+		return 0, err
+	}
+
+	if found {
+		_, err = sqltrace.Exec(tx, sqltrace.TargetPrimary, "UPDATE id_nodes SET owner = $1, expires_at = $2 WHERE node_id = $3", owner, expires, node)
+		return node, err
+	}
+
+	next, _, err := queryNode(tx, "SELECT COALESCE(MAX(node_id), -1) + 1 FROM id_nodes")
+	if err != nil {
+		return 0, err
+	}
+
+	if next > ids.SnowflakeMaxNode {
+		return 0, ErrNoFreeNode
+	}
+
+	result, err := sqltrace.Exec(tx, sqltrace.TargetPrimary, "INSERT INTO id_nodes (node_id, owner, expires_at) VALUES ($1, $2, $3) ON CONFLICT (node_id) DO NOTHING", next, owner, expires)
+	if err != nil {
+		return 0, err
+	}
+
+	if count, err := result.RowsAffected(); err != nil || count == 0 {
+		return 0, errNodeTaken
+	}
+
+	return next, nil
+
+}
+
+// queryNode reads a single node id, reporting whether a row was found.
+func queryNode(tx *sql.Tx, query string, args ...interface{}) (int, bool, error) {
+
+	rows, err := sqltrace.Query(tx, sqltrace.TargetPrimary, query, args...)
+	defer rows.Close()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !rows.Next() {
+		return 0, false, rows.Err()
+	}
+
+	var node int
+	err = rows.Scan(&node)
+
+	return node, err == nil, err
+
+}
+
+/*
+renew extends the lease until it is released. If the lease is lost, or the
+next attempt would come after it expires, the snowflake generator is disabled
+and renewal stops, since another process may take the node id.
+*/
+func (lease *NodeLease) renew() {
+
+	interval := lease.ttl / 3
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lease.stop:
+			return
+		case <-ticker.C:
+			expires := time.Now().Add(lease.ttl)
+			result, err := sqltrace.Exec(Primary, sqltrace.TargetPrimary, "UPDATE id_nodes SET expires_at = $1 WHERE node_id = $2 AND owner = $3", expires.UTC(), lease.Node, lease.Owner)
+			if err != nil {
+				if time.Now().Add(interval).Before(lease.expires) {
+					logger.Warnf("Unable to renew snowflake node %v: %v", lease.Node, err)
+					continue
+				}
+				lease.lose(fmt.Sprintf("could not be renewed before it expires: %v", err))
+				return
+			}
+			if count, err := result.RowsAffected(); err != nil || count == 0 {
+				lease.lose("was taken by another owner")
+				return
+			}
+			lease.expires = expires
+		}
+	}
+
+}
+
+// lose disables the snowflake generator after the lease is lost.
+func (lease *NodeLease) lose(reason string) {
+
+	ids.DisableSnowflake(ErrNodeLeaseLost)
+
+	logger.Errorf("Snowflake node %v lease %v; snowflake ids are disabled", lease.Node, reason)
+
+}
+
+// Release stops renewing the lease and frees the node id for other processes.
+// The default snowflake generator stops issuing ids with the node id.
+func (lease *NodeLease) Release() error {
+
+	lease.stopOnce.Do(func() { close(lease.stop) })
+
+	ids.DisableSnowflake(ids.ErrNodeNotConfigured)
+
+	_, err := sqltrace.Exec(Primary, sqltrace.TargetPrimary, "UPDATE id_nodes SET expires_at = $1 WHERE node_id = $2 AND owner = $3", time.Unix(0, 0).UTC(), lease.Node, lease.Owner)
+
+	return err
+
+}
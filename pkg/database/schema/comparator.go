@@ -6,7 +6,6 @@ import (
 
 	"github.com/production-grid/pgrid-core/pkg/graph"
 	"github.com/production-grid/pgrid-core/pkg/loaders"
-	"github.com/production-grid/pgrid-core/pkg/logging"
 )
 
 /*
@@ -61,8 +60,8 @@ func Compare(sourceModel Model, targetModel Model, changers []SchemaChanger, dia
 	sourceMap := buildTableMap(sourceModel)
 	targetMap := buildTableMap(targetModel)
 
-	logging.Infoln("Source Tables: ", len(sourceMap))
-	logging.Infoln("Target Tables: ", len(targetMap))
+	logger.Infoln("Source Tables: ", len(sourceMap))
+	logger.Infoln("Target Tables: ", len(targetMap))
 
 	//sortedTables := sortTables(targetMap)
 
@@ -161,7 +160,7 @@ func compareTable(targetTable Table, sourceMap map[string]Table) ([]Change, []Ch
 
 func hasSize(dataType string) bool {
 	switch dataType {
-	case "TEXT", "BIT", "INTEGER":
+	case "TEXT", "BIT", "INTEGER", "BIGINT":
 		return false
 	default:
 		return true
@@ -203,14 +202,14 @@ func compareTables(existingTable Table, targetTable Table) ([]Change, []Change)
 			} else if hasSize(targetCol.DataType) {
 				if targetCol.Size != existingCol.Size {
 					if targetCol.Size < existingCol.Size {
-						logging.Warnf("Ignoring size change for %s since it might truncate data", targetCol.Name)
+						logger.Warnf("Ignoring size change for %s since it might truncate data", targetCol.Name)
 						continue
 					}
 					results = append(results, Change{ChangeType: ModifyColumn, Table: targetTable, Column: targetCol, OldColumn: existingCol, Reason: "size mismatch"})
 				} else if targetCol.Decimal != existingCol.Decimal {
-					logging.Error("Decimal Change")
+					logger.Error("Decimal Change")
 					if targetCol.Decimal < existingCol.Decimal {
-						logging.Warnf("Ignoring size change for %s since it might truncate data", targetCol.Name)
+						logger.Warnf("Ignoring size change for %s since it might truncate data", targetCol.Name)
 						continue
 					}
 					results = append(results, Change{ChangeType: ModifyColumn, Table: targetTable, Column: targetCol, OldColumn: existingCol, Reason: "decimal scale mismatch"})
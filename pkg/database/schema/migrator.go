@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
 	"github.com/production-grid/pgrid-core/pkg/loaders"
 	"github.com/production-grid/pgrid-core/pkg/logging"
 
@@ -24,6 +25,7 @@ const (
 
 var (
 	insideParens = regexp.MustCompile(`\(([^()]*)\)`)
+	logger       = logging.For("schema")
 )
 
 /*
@@ -47,10 +49,10 @@ func (migrator *DefaultMigrator) Migrate(loader loaders.ResourceLoader, schemaFi
 	// Take the migrator lock or return
 	/*
 		if err := addMigratorLock(filepath.Base(schemaPath)); err == nil {
-			logging.Warn("Took schematron migrator lock")
+			logger.Warn("Took schematron migrator lock")
 			defer removeMigratorLock(filepath.Base(schemaPath))
 		} else {
-			logging.Warn("Schematron lock is taken - doing nothing")
+			logger.Warn("Schematron lock is taken - doing nothing")
 			return nil
 		}
 	*/
@@ -155,7 +157,7 @@ func (migrator *DefaultMigrator) executeCreateTable(change Change) error {
 
 	//TODO method too long - should be caught once we add uncle bob style checking
 
-	logging.Infof("Creating Table: %s\n", change.Table.Name)
+	logger.Infof("Creating Table: %s\n", change.Table.Name)
 
 	pkCols := make([]string, 0)
 
@@ -176,9 +178,9 @@ func (migrator *DefaultMigrator) executeCreateTable(change Change) error {
 		sql += pkDef
 	}
 	sql += ")"
-	logging.Infoln("Executing:", sql)
+	logger.Infoln("Executing:", sql)
 
-	_, err := migrator.Datasource.Exec(sql)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, sql)
 	if err != nil {
 		fmt.Println(err.Error())
 		return err
@@ -189,11 +191,11 @@ func (migrator *DefaultMigrator) executeCreateTable(change Change) error {
 
 func (migrator *DefaultMigrator) executeQuery(change Change) error {
 
-	logging.Infof("Executing Query Change: %s", change.Query)
+	logger.Infof("Executing Query Change: %s", change.Query)
 
-	_, err := migrator.Datasource.Exec(change.Query)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, change.Query)
 	if err != nil {
-		logging.Errorf("Failed to execute query: %v", err)
+		logger.Errorf("Failed to execute query: %v", err)
 		return err
 	}
 
@@ -203,18 +205,18 @@ func (migrator *DefaultMigrator) executeQuery(change Change) error {
 
 func (migrator *DefaultMigrator) executeAddForeignKey(change Change) error {
 
-	logging.Infof("Adding Foreign Key: %s:%s\n", change.Table.Name, change.Column.ForeignKey.Name)
+	logger.Infof("Adding Foreign Key: %s:%s\n", change.Table.Name, change.Column.ForeignKey.Name)
 
 	sql := "alter table "
 	sql += change.Table.Name
 	sql += " add "
 	sql += migrator.Dialecter.ForeignKeyDefinition(change.Column)
 
-	logging.Infoln("Executing:", sql)
+	logger.Infoln("Executing:", sql)
 
-	_, err := migrator.Datasource.Exec(sql)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, sql)
 	if err != nil {
-		logging.Errorf("Failed to add foreign key: %v", err)
+		logger.Errorf("Failed to add foreign key: %v", err)
 		return err
 	}
 
@@ -223,15 +225,15 @@ func (migrator *DefaultMigrator) executeAddForeignKey(change Change) error {
 
 func (migrator *DefaultMigrator) executeModifyColumn(change Change) error {
 
-	logging.Infof("Modifying Column: %s:%s\n", change.Table.Name, change.Column.Name)
+	logger.Infof("Modifying Column: %s:%s\n", change.Table.Name, change.Column.Name)
 
 	sql := migrator.Dialecter.ModifyColumn(change)
 
-	logging.Infoln("Executing:", sql)
+	logger.Infoln("Executing:", sql)
 
-	_, err := migrator.Datasource.Exec(sql)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, sql)
 	if err != nil {
-		logging.Errorf("Failed to modify column: %v", err)
+		logger.Errorf("Failed to modify column: %v", err)
 		return err
 	}
 
@@ -240,18 +242,18 @@ func (migrator *DefaultMigrator) executeModifyColumn(change Change) error {
 
 func (migrator *DefaultMigrator) executeAddColumn(change Change) error {
 
-	logging.Infof("Adding Column: %s:%s\n", change.Table.Name, change.Column.Name)
+	logger.Infof("Adding Column: %s:%s\n", change.Table.Name, change.Column.Name)
 
 	sql := "alter table "
 	sql += change.Table.Name
 	sql += " add column "
 	sql += migrator.Dialecter.ColumnDefinition(change.Column)
 
-	logging.Infoln("Executing:", sql)
+	logger.Infoln("Executing:", sql)
 
-	_, err := migrator.Datasource.Exec(sql)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, sql)
 	if err != nil {
-		logging.Error(err.Error())
+		logger.Error(err.Error())
 		return err
 	}
 
@@ -260,15 +262,15 @@ func (migrator *DefaultMigrator) executeAddColumn(change Change) error {
 
 func (migrator *DefaultMigrator) executeCreateIndex(change Change) error {
 
-	logging.Infof("Creating Table: %s\n", change.Table.Name)
+	logger.Infof("Creating Table: %s\n", change.Table.Name)
 
 	sql := "create " + migrator.Dialecter.IndexDefinition(change.Table, change.Index)
 
-	logging.Infoln("Executing:", sql)
+	logger.Infoln("Executing:", sql)
 
-	_, err := migrator.Datasource.Exec(sql)
+	_, err := sqltrace.Exec(migrator.Datasource, sqltrace.TargetPrimary, sql)
 	if err != nil {
-		logging.Errorf("Failed to create index: %v", err)
+		logger.Errorf("Failed to create index: %v", err)
 		return err
 	}
 
@@ -312,7 +314,7 @@ func ReadModelFromSchemaFile(loader loaders.ResourceLoader, schemaFile string) (
 		return nil, err
 	}
 
-	logging.Debugf("Tables in schema file: %+v", len(model.Tables))
+	logger.Debugf("Tables in schema file: %+v", len(model.Tables))
 
 	//validate model
 	for _, tbl := range model.Tables {
@@ -376,7 +378,7 @@ func sanitizeTable(table Table) Table {
 				if len(tokens) > 0 {
 					size, err := strconv.Atoi(tokens[0])
 					if err != nil {
-						logging.Warnln("error parsing size token", err)
+						logger.Warnln("error parsing size token", err)
 					} else {
 						col.Size = size
 					}
@@ -384,13 +386,13 @@ func sanitizeTable(table Table) Table {
 				if len(tokens) > 1 {
 					decimal, err := strconv.Atoi(tokens[1])
 					if err != nil {
-						logging.Warnln("error parsing decimal precision token", err)
+						logger.Warnln("error parsing decimal precision token", err)
 					} else {
 						col.Decimal = decimal
 					}
 				}
 				if len(tokens) > 2 {
-					logging.Warnln("data type has more size tokens than expected")
+					logger.Warnln("data type has more size tokens than expected")
 				}
 			}
 		}
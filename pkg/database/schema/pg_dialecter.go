@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
+
 	//used to bring in the postgres driver
 	_ "github.com/lib/pq"
 )
@@ -250,7 +252,7 @@ func (psql *PostgresDialect) ReadTableModel(db *sql.DB, tableName string) (Table
 		return result, err
 	}
 
-	rows, err := db.Query("SELECT column_name, data_type, is_nullable, column_default, character_maximum_length, numeric_precision, numeric_scale FROM information_schema.columns where TABLE_NAME = $1", tableName)
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, "SELECT column_name, data_type, is_nullable, column_default, character_maximum_length, numeric_precision, numeric_scale FROM information_schema.columns where TABLE_NAME = $1", tableName)
 	defer rows.Close()
 	if err != nil {
 		return result, err
@@ -313,7 +315,7 @@ func readIndexes(db *sql.DB, table Table) (Table, error) {
 	query := `select indexname, indexdef
 						from pg_indexes where tablename = $1`
 
-	rows, err := db.Query(query, table.Name)
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, query, table.Name)
 	defer rows.Close()
 	if err != nil {
 		return table, err
@@ -361,7 +363,7 @@ func getPrimaryKeys(db *sql.DB, table *Table) ([]string, error) {
 						AND tc.table_name = c.table_name AND ccu.column_name = c.column_name
 						WHERE constraint_type = 'PRIMARY KEY' and tc.table_name = $1`
 
-	rows, err := db.Query(query, table.Name)
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, query, table.Name)
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -394,7 +396,7 @@ func readForeignKeys(db *sql.DB, table Table) (map[string]ForeignKey, error) {
 						      AND ccu.table_schema = tc.table_schema
 						WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_name=$1`
 
-	rows, err := db.Query(query, table.Name)
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, query, table.Name)
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -419,7 +421,7 @@ func readForeignKeys(db *sql.DB, table Table) (map[string]ForeignKey, error) {
 
 func processCreateTable(db *sql.DB, table Table) (Table, error) {
 
-	rows, err := db.Query("show create table " + table.Name)
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, "show create table "+table.Name)
 	defer rows.Close()
 	if err != nil {
 		return table, err
@@ -482,7 +484,7 @@ func (psql *PostgresDialect) ReadTableList(db *sql.DB) ([]string, error) {
 
 	results := make([]string, 0)
 
-	rows, err := db.Query("select tablename from pg_catalog.pg_tables where schemaname = 'public'")
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary, "select tablename from pg_catalog.pg_tables where schemaname = 'public'")
 	defer rows.Close()
 	if err != nil {
 		fmt.Println(err.Error())
@@ -503,7 +505,7 @@ func (psql *PostgresDialect) ReadTableList(db *sql.DB) ([]string, error) {
 func (psql *PostgresDialect) ReadTriggers(db *sql.DB) ([]Trigger, error) {
 	results := make([]Trigger, 0)
 
-	rows, err := db.Query(
+	rows, err := sqltrace.Query(db, sqltrace.TargetPrimary,
 		`SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_STATEMENT, ACTION_TIMING
 FROM information_schema.TRIGGERS
 WHERE TRIGGER_SCHEMA = DATABASE()`)
@@ -0,0 +1,281 @@
+package sqltrace
+
+import (
+	"database/sql"
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// enumerates the statement targets
+const (
+	TargetPrimary = "primary"
+	TargetReplica = "replica"
+)
+
+// DefaultSlowThreshold is used when no slow query threshold is configured.
+const DefaultSlowThreshold = 500 * time.Millisecond
+
+// databasePackages prefixes the packages skipped when resolving the caller of
+// a statement.
+const databasePackages = "github.com/production-grid/pgrid-core/pkg/database/"
+
+var slowThreshold = int64(DefaultSlowThreshold)
+
+// Execer is implemented by *sql.DB and *sql.Tx.
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Querier is implemented by *sql.DB and *sql.Tx.
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SetSlowThreshold sets the duration above which statements are logged as slow
+// queries. Zero restores the default.
+func SetSlowThreshold(threshold time.Duration) {
+	if threshold <= 0 {
+		threshold = DefaultSlowThreshold
+	}
+	atomic.StoreInt64(&slowThreshold, int64(threshold))
+}
+
+// SlowThreshold returns the current slow query threshold.
+func SlowThreshold() time.Duration {
+	return time.Duration(atomic.LoadInt64(&slowThreshold))
+}
+
+// Exec executes a statement and records its duration and affected row count.
+// The target names the database the statement runs against, usually primary
+// or replica.
+func Exec(execer Execer, target string, query string, args ...interface{}) (sql.Result, error) {
+
+	stmt := start(target, query, args)
+
+	result, err := execer.Exec(query, args...)
+
+	var count int64 = -1
+	if err == nil {
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			count = affected
+		}
+	}
+
+	stmt.finish(count, err)
+
+	return result, err
+
+}
+
+// Query executes a query and returns rows that record the statement's duration
+// and row count when closed. The returned rows are nil if the query fails, and
+// closing nil rows is safe.
+func Query(querier Querier, target string, query string, args ...interface{}) (*Rows, error) {
+
+	stmt := start(target, query, args)
+
+	rows, err := querier.Query(query, args...)
+
+	if err != nil {
+		stmt.finish(0, err)
+		return nil, err
+	}
+
+	return &Rows{Rows: rows, stmt: stmt}, nil
+
+}
+
+/*
+Traced wraps a querier so its queries are traced against the target, for
+packages that take a plain querier and shouldn't depend on this one. The rows
+are returned unwrapped, so they aren't counted; use Query where the count
+matters.
+*/
+func Traced(querier Querier, target string) Querier {
+	return tracedQuerier{querier: querier, target: target}
+}
+
+type tracedQuerier struct {
+	querier Querier
+	target  string
+}
+
+func (traced tracedQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+
+	stmt := start(traced.target, query, args)
+
+	rows, err := traced.querier.Query(query, args...)
+
+	stmt.finish(-1, err)
+
+	return rows, err
+
+}
+
+// Rows wraps sql.Rows in order to count rows as they are read.
+type Rows struct {
+	*sql.Rows
+	stmt   *statement
+	count  int64
+	closed bool
+}
+
+// Next advances to the next row and counts it.
+func (rows *Rows) Next() bool {
+
+	if rows.Rows.Next() {
+		rows.count++
+		return true
+	}
+
+	return false
+
+}
+
+// Close closes the underlying rows and records the statement.
+func (rows *Rows) Close() error {
+
+	if rows == nil {
+		return nil
+	}
+
+	err := rows.Rows.Close()
+
+	if !rows.closed {
+		rows.closed = true
+		if err == nil {
+			err = rows.Rows.Err()
+		}
+		rows.stmt.finish(rows.count, err)
+	}
+
+	return err
+
+}
+
+// statement holds the details of a statement while it executes.
+type statement struct {
+	target        string
+	query         string
+	argumentTypes []string
+	caller        string
+	logger        *logging.Logger
+	started       time.Time
+}
+
+func start(target string, query string, args []interface{}) *statement {
+
+	argumentTypes := make([]string, len(args))
+	for idx, arg := range args {
+		argumentTypes[idx] = fmt.Sprintf("%T", arg)
+	}
+
+	caller, module := resolveCaller()
+
+	return &statement{
+		target:        target,
+		query:         query,
+		argumentTypes: argumentTypes,
+		caller:        caller,
+		logger:        logging.For(module),
+		started:       time.Now(),
+	}
+
+}
+
+/*
+finish logs the statement. Statements are logged by the logger of the module
+that issued them, e.g. relational or schema, so their trace level can be set
+per module. Bound parameter values are never logged, only their types.
+*/
+func (stmt *statement) finish(rows int64, err error) {
+
+	logger := stmt.logger
+
+	duration := time.Since(stmt.started)
+	slow := duration >= SlowThreshold()
+
+	if !slow && !logger.IsLevelEnabled(logrus.TraceLevel) {
+		return
+	}
+
+	fields := logrus.Fields{
+		"target":      stmt.target,
+		"duration_ms": float64(duration) / float64(time.Millisecond),
+		"rows":        rows,
+		"caller":      stmt.caller,
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	if slow {
+		fields["param_types"] = strings.Join(stmt.argumentTypes, ",")
+		logger.WithFields(fields).Warnln("Slow SQL:", stmt.query)
+		return
+	}
+
+	logger.WithFields(fields).Traceln("SQL:", stmt.query)
+
+}
+
+/*
+resolveCaller returns the first function outside the database packages that
+led to the statement, or the first function outside this package if the
+statement originated in the database packages. It also returns the package
+name of the first function outside this package, which names the module
+logger.
+*/
+func resolveCaller() (string, string) {
+
+	pcs := make([]uintptr, 16)
+	count := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+
+	fallback := ""
+	module := ""
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, databasePackages+"sqltrace.") {
+			described := fmt.Sprintf("%s:%d", path.Base(frame.Function), frame.Line)
+			if module == "" {
+				module = packageName(frame.Function)
+			}
+			if !strings.HasPrefix(frame.Function, databasePackages) {
+				return described, module
+			}
+			if fallback == "" {
+				fallback = described
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	return fallback, module
+
+}
+
+// packageName returns the package name of a fully qualified function name,
+// or sql if there is none.
+func packageName(function string) string {
+
+	name := function[strings.LastIndex(function, "/")+1:]
+
+	if idx := strings.Index(name, "."); idx > 0 {
+		return name[:idx]
+	}
+
+	return "sql"
+
+}
@@ -0,0 +1,104 @@
+package sqltrace
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type slowExecer struct {
+	delay time.Duration
+}
+
+func (execer *slowExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	time.Sleep(execer.delay)
+	return driver.RowsAffected(3), nil
+}
+
+type captureHook struct {
+	entries []*logrus.Entry
+}
+
+func (hook *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *captureHook) Fire(entry *logrus.Entry) error {
+	hook.entries = append(hook.entries, entry)
+	return nil
+}
+
+func TestSlowQueryLogging(t *testing.T) {
+
+	assert := assert.New(t)
+
+	hook := &captureHook{}
+	logging.AddHook(hook)
+
+	SetSlowThreshold(time.Millisecond)
+	defer SetSlowThreshold(0)
+
+	_, err := Exec(&slowExecer{delay: 2 * time.Millisecond}, TargetPrimary, "update users set email = $1 where id = $2", "secret@example.com", 42)
+	assert.NoError(err)
+
+	if assert.Len(hook.entries, 1) {
+		entry := hook.entries[0]
+		assert.Equal(logrus.WarnLevel, entry.Level)
+		assert.Equal(TargetPrimary, entry.Data["target"])
+		assert.Equal(int64(3), entry.Data["rows"])
+		assert.Equal("string,int", entry.Data["param_types"])
+		assert.NotEmpty(entry.Data["caller"])
+		assert.NotEmpty(entry.Data["module"])
+		assert.NotContains(entry.Message, "secret")
+	}
+
+}
+
+type slowQuerier struct {
+	delay time.Duration
+}
+
+func (querier *slowQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	time.Sleep(querier.delay)
+	return nil, errors.New("no rows")
+}
+
+func TestTracedQuerier(t *testing.T) {
+
+	assert := assert.New(t)
+
+	hook := &captureHook{}
+	logging.AddHook(hook)
+
+	SetSlowThreshold(time.Millisecond)
+	defer SetSlowThreshold(0)
+
+	_, err := Traced(&slowQuerier{delay: 2 * time.Millisecond}, TargetReplica).Query("select slug from productions where slug = $1", "hamlet")
+	assert.Error(err)
+
+	if assert.Len(hook.entries, 1) {
+		entry := hook.entries[0]
+		assert.Equal(TargetReplica, entry.Data["target"])
+		assert.Equal(int64(-1), entry.Data["rows"])
+		assert.Equal("no rows", entry.Data["error"])
+		assert.Equal("string", entry.Data["param_types"])
+	}
+
+}
+
+func TestPackageName(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal("relational", packageName("github.com/production-grid/pgrid-core/pkg/database/relational.(*NodeLease).renew"))
+	assert.Equal("schema", packageName("github.com/production-grid/pgrid-core/pkg/database/schema.PreMigrate.func1"))
+	assert.Equal("main", packageName("main.main"))
+	assert.Equal("sql", packageName(""))
+
+}
@@ -0,0 +1,166 @@
+package ids
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// crockfordCheckAlphabet extends the base32 alphabet with the five extra
+	// symbols Crockford defines for mod 37 check characters.
+	crockfordCheckAlphabet = crockfordAlphabet + "*~$=U"
+	crockfordCheckModulus  = 37
+
+	defaultCodeSeparator = "-"
+)
+
+// Code errors.
+var (
+	ErrInvalidCode     = errors.New("invalid code")
+	ErrCodeCheckFailed = errors.New("code check character does not match")
+)
+
+// CodeFormat describes a family of human friendly codes. Codes are made of
+// Crockford base32 characters followed by a check character, so that typos
+// and transpositions are caught before hitting the database.
+type CodeFormat struct {
+	Length    int    // number of random characters, not counting the check character
+	GroupSize int    // characters per group when displayed, zero for no grouping
+	Separator string // group separator, defaults to a hyphen
+}
+
+// Standard code formats.
+var (
+	ActivationCodeFormat = CodeFormat{Length: 5}
+	TicketCodeFormat     = CodeFormat{Length: 9, GroupSize: 5}
+	GiftCardCodeFormat   = CodeFormat{Length: 15, GroupSize: 4}
+)
+
+/*
+New generates a random code in canonical form, meaning upper case with the
+check character and without separators.
+*/
+func (format CodeFormat) New() string {
+	return DefaultGenerator().NewCode(format)
+}
+
+/*
+Display formats a canonical code for display by splitting it into groups.
+*/
+func (format CodeFormat) Display(code string) string {
+
+	if format.GroupSize <= 0 || len(code) <= format.GroupSize {
+		return code
+	}
+
+	separator := format.Separator
+	if separator == "" {
+		separator = defaultCodeSeparator
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(code); i += format.GroupSize {
+		if i > 0 {
+			b.WriteString(separator)
+		}
+		end := i + format.GroupSize
+		if end > len(code) {
+			end = len(code)
+		}
+		b.WriteString(code[i:end])
+	}
+
+	return b.String()
+
+}
+
+/*
+Parse converts user input to a canonical code. It ignores case, spaces and
+separators, maps the commonly confused letters O, I and L to the digits they
+resemble, and verifies the check character.
+*/
+func (format CodeFormat) Parse(input string) (string, error) {
+
+	separator := format.Separator
+	if separator == "" {
+		separator = defaultCodeSeparator
+	}
+
+	normalized := normalizeCode(input, separator)
+
+	if len(normalized) != format.Length+1 {
+		return "", ErrInvalidCode
+	}
+
+	return parseChecked(normalized)
+
+}
+
+/*
+Validate returns true if the input parses as a valid code.
+*/
+func (format CodeFormat) Validate(input string) bool {
+	_, err := format.Parse(input)
+	return err == nil
+}
+
+// normalizeCode upper cases input and strips spaces and separators.
+func normalizeCode(input string, separator string) string {
+	return strings.NewReplacer(separator, "", " ", "", "-", "").Replace(strings.ToUpper(input))
+}
+
+// parseChecked maps ambiguous characters in a normalized code and verifies its
+// trailing check character.
+func parseChecked(normalized string) (string, error) {
+
+	if len(normalized) < 2 {
+		return "", ErrInvalidCode
+	}
+
+	length := len(normalized) - 1
+	data := []byte(normalized[:length])
+
+	for i, c := range data {
+		c = mapAmbiguous(c)
+		if strings.IndexByte(crockfordAlphabet, c) < 0 {
+			return "", ErrInvalidCode
+		}
+		data[i] = c
+	}
+
+	check := mapAmbiguous(normalized[length])
+	if strings.IndexByte(crockfordCheckAlphabet, check) < 0 {
+		return "", ErrInvalidCode
+	}
+
+	if crockfordCheckAlphabet[checkValue(string(data))] != check {
+		return "", ErrCodeCheckFailed
+	}
+
+	return string(data) + string(check), nil
+
+}
+
+// mapAmbiguous maps letters that are easily mistaken for digits.
+func mapAmbiguous(c byte) byte {
+	switch c {
+	case 'O':
+		return '0'
+	case 'I', 'L':
+		return '1'
+	}
+	return c
+}
+
+// checkValue computes the mod 37 check value of a canonical code.
+func checkValue(code string) int {
+
+	value := 0
+	for i := 0; i < len(code); i++ {
+		value = (value*32 + strings.IndexByte(crockfordAlphabet, code[i])) % crockfordCheckModulus
+	}
+
+	return value
+
+}
@@ -0,0 +1,93 @@
+package ids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateActivationCode(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.True(ValidateActivationCode(NewActivationCode()))
+	assert.True(ValidateActivationCode("ABC234"))
+	assert.False(ValidateActivationCode("ABC230"), "zero is not an activation character")
+	assert.False(ValidateActivationCode("abc234"))
+	assert.False(ValidateActivationCode("ABC23"))
+
+}
+
+func TestCodeFormat(t *testing.T) {
+
+	assert := assert.New(t)
+
+	for _, format := range []CodeFormat{ActivationCodeFormat, TicketCodeFormat, GiftCardCodeFormat} {
+		code := format.New()
+		assert.Len(code, format.Length+1)
+
+		parsed, err := format.Parse(format.Display(code))
+		assert.NoError(err)
+		assert.Equal(code, parsed)
+	}
+
+	assert.Equal("ABCD-EFGH-JKMN-PQRS", GiftCardCodeFormat.Display("ABCDEFGHJKMNPQRS"))
+	assert.Equal("ABCDE FGHJK", CodeFormat{Length: 9, GroupSize: 5, Separator: " "}.Display("ABCDEFGHJK"))
+
+}
+
+func TestCodeParsing(t *testing.T) {
+
+	format := CodeFormat{Length: 4, GroupSize: 2}
+
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+		err    error
+	}{
+		{
+			name:   "Canonical",
+			input:  "0123J",
+			expect: "0123J",
+		},
+		{
+			name:   "Ambiguous",
+			input:  "OI23j",
+			expect: "0123J",
+		},
+		{
+			name:   "AmbiguousL",
+			input:  "ol-23 j",
+			expect: "0123J",
+		},
+		{
+			name:  "Transposed",
+			input: "1023J",
+			err:   ErrCodeCheckFailed,
+		},
+		{
+			name:  "TooShort",
+			input: "012J",
+			err:   ErrInvalidCode,
+		},
+		{
+			name:  "BadCharacter",
+			input: "01U3J",
+			err:   ErrInvalidCode,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := format.Parse(test.input)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, result)
+		})
+	}
+
+}
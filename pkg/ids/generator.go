@@ -0,0 +1,328 @@
+package ids
+
+import (
+	crand "crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	mrand "math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Generator generates random ids and codes from an entropy source and a clock.
+The package level functions use a default generator backed by crypto/rand and
+the system clock. Tests can build a generator with NewSeededGenerator and a
+fixed clock to get reproducible output.
+*/
+type Generator struct {
+	mu      sync.Mutex
+	entropy io.Reader
+	rand    *mrand.Rand
+	now     func() time.Time
+
+	ulidLastTime    uint64
+	ulidLastEntropy [ulidEntropyLength]byte
+
+	v7LastTime uint64
+	v7LastSeq  uint16
+}
+
+var (
+	defaultGeneratorLock sync.RWMutex
+	defaultGenerator     = NewGenerator(crand.Reader, time.Now)
+)
+
+/*
+NewGenerator returns a generator that reads randomness from entropy and the
+time from now. A nil entropy source defaults to crypto/rand and a nil clock to
+time.Now.
+*/
+func NewGenerator(entropy io.Reader, now func() time.Time) *Generator {
+
+	if entropy == nil {
+		entropy = crand.Reader
+	}
+
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Generator{
+		entropy: entropy,
+		rand:    mrand.New(&readerSrc{reader: entropy}),
+		now:     now,
+	}
+
+}
+
+/*
+NewSeededGenerator returns a deterministic generator for tests. Generators with
+the same seed and clock produce the same sequence of ids. Never use it outside
+tests.
+*/
+func NewSeededGenerator(seed int64, now func() time.Time) *Generator {
+	return NewGenerator(mrand.New(mrand.NewSource(seed)), now)
+}
+
+// FixedClock returns a clock that always reports the given time.
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+// DefaultGenerator returns the generator used by the package level functions.
+func DefaultGenerator() *Generator {
+
+	defaultGeneratorLock.RLock()
+	defer defaultGeneratorLock.RUnlock()
+
+	return defaultGenerator
+
+}
+
+/*
+SetDefaultGenerator replaces the generator used by the package level functions
+and returns the previous one, so tests can restore it when they're done.
+*/
+func SetDefaultGenerator(gen *Generator) *Generator {
+
+	defaultGeneratorLock.Lock()
+	defer defaultGeneratorLock.Unlock()
+
+	previous := defaultGenerator
+	defaultGenerator = gen
+
+	return previous
+
+}
+
+/*
+RandomBytes returns a random slice of bytes of the given length.
+*/
+func (gen *Generator) RandomBytes(length int) []byte {
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	return gen.randomBytes(length)
+
+}
+
+/*
+NewSecureID generates a random 16 byte ID in Base32 encoding.
+*/
+func (gen *Generator) NewSecureID() string {
+
+	rawBytes := gen.RandomBytes(16)
+	return strings.Replace(base32.StdEncoding.EncodeToString(rawBytes), "=", "", -1)
+
+}
+
+/*
+NewDigitCode generates a random code with digits.
+*/
+func (gen *Generator) NewDigitCode(length int) string {
+	const digits = "1234567890"
+
+	return gen.randomString(length, digits)
+}
+
+/*
+NewActivationCode generates a random 6 character activation code.
+*/
+func (gen *Generator) NewActivationCode() string {
+	return gen.randomString(activationCodeLength, activationChars)
+}
+
+/*
+NewCode generates a random code in the given format.
+*/
+func (gen *Generator) NewCode(format CodeFormat) string {
+
+	code := gen.randomString(format.Length, crockfordAlphabet)
+
+	return code + string(crockfordCheckAlphabet[checkValue(code)])
+
+}
+
+/*
+NewHmacKey generates a random key for use in computing HMAC's.
+*/
+func (gen *Generator) NewHmacKey() string {
+
+	rawBytes := gen.RandomBytes(32)
+	return hex.EncodeToString(rawBytes)
+
+}
+
+/*
+NewULID generates a monotonic ULID using the generator's clock and entropy.
+*/
+func (gen *Generator) NewULID() string {
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	now := uint64(gen.now().UnixNano() / int64(time.Millisecond))
+
+	if now > gen.ulidLastTime {
+		gen.ulidLastTime = now
+		copy(gen.ulidLastEntropy[:], gen.randomBytes(ulidEntropyLength))
+	} else if !incrementEntropy(&gen.ulidLastEntropy) {
+		//the random component overflowed, so borrow the next millisecond
+		gen.ulidLastTime++
+		copy(gen.ulidLastEntropy[:], gen.randomBytes(ulidEntropyLength))
+	}
+
+	return encodeULID(gen.ulidLastTime, gen.ulidLastEntropy)
+
+}
+
+/*
+NewUUIDv4 generates a random UUID.
+*/
+func (gen *Generator) NewUUIDv4() UUID {
+
+	var u UUID
+	copy(u[:], gen.RandomBytes(16))
+	u.setVersion(UUIDVersion4)
+
+	return u
+
+}
+
+/*
+NewUUIDv7 generates a time ordered UUID using the generator's clock and
+entropy.
+*/
+func (gen *Generator) NewUUIDv7() UUID {
+
+	gen.mu.Lock()
+	now := uint64(gen.now().UnixNano() / int64(time.Millisecond))
+	if now > gen.v7LastTime {
+		gen.v7LastTime = now
+		seed := gen.randomBytes(2)
+		//leave headroom so the counter rarely overflows
+		gen.v7LastSeq = (uint16(seed[0])<<8 | uint16(seed[1])) & 0x7ff
+	} else {
+		gen.v7LastSeq++
+		if gen.v7LastSeq > 0xfff {
+			gen.v7LastTime++
+			gen.v7LastSeq = 0
+		}
+	}
+	ms, seq := gen.v7LastTime, gen.v7LastSeq
+	tail := gen.randomBytes(8)
+	gen.mu.Unlock()
+
+	var u UUID
+	for i := 0; i < 6; i++ {
+		u[i] = byte(ms >> uint(40-8*i))
+	}
+	u[6] = byte(seq >> 8)
+	u[7] = byte(seq)
+	copy(u[8:], tail)
+	u.setVersion(UUIDVersion7)
+
+	return u
+
+}
+
+/*
+NewBase32UUID generates a new Base32 encoded random UUID.
+*/
+func (gen *Generator) NewBase32UUID() string {
+
+	u := gen.NewUUIDv4()
+	return strings.Replace(base32.StdEncoding.EncodeToString(u[:]), "=", "", -1)
+
+}
+
+/*
+NewBase64UUID generates a new Base64 encoded random UUID.
+*/
+func (gen *Generator) NewBase64UUID() string {
+
+	u := gen.NewUUIDv4()
+	return strings.Replace(base64.StdEncoding.EncodeToString(u[:]), "=", "", -1)
+
+}
+
+// Intn returns a random number in [0,n) from the generator's entropy source.
+func (gen *Generator) Intn(n int) int {
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	return gen.rand.Intn(n)
+
+}
+
+// randomBytes reads from the entropy source. The caller must hold the lock.
+func (gen *Generator) randomBytes(length int) []byte {
+
+	result := make([]byte, length)
+	io.ReadFull(gen.entropy, result)
+
+	return result
+
+}
+
+// randomString returns a random string given a pool of characters and a
+// length.
+func (gen *Generator) randomString(length int, pool string) string {
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	result := make([]byte, length)
+
+	for i := 0; i < length; i++ {
+		result[i] = pool[gen.rand.Intn(len(pool))]
+	}
+
+	return string(result)
+
+}
+
+// readerSrc implements the mrand.Source interface on top of an entropy
+// source.
+type readerSrc struct {
+	reader io.Reader
+}
+
+func (s *readerSrc) Seed(seed int64) { /*Results come from the reader, so seeding is no-op.*/
+}
+
+func (s *readerSrc) Uint64() (value uint64) {
+	binary.Read(s.reader, binary.BigEndian, &value)
+	return
+}
+
+func (s *readerSrc) Int63() int64 {
+	return int64(s.Uint64() & ^(uint64(1 << 63)))
+}
+
+// defaultSrc implements the mrand.Source interface by delegating to the
+// default generator.
+type defaultSrc struct{}
+
+func (s defaultSrc) Seed(seed int64) { /*Seed the default generator instead.*/
+}
+
+func (s defaultSrc) Int63() int64 {
+
+	gen := DefaultGenerator()
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	return gen.rand.Int63()
+
+}
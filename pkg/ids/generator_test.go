@@ -0,0 +1,74 @@
+package ids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededGenerator(t *testing.T) {
+
+	assert := assert.New(t)
+
+	clock := FixedClock(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+
+	first := NewSeededGenerator(42, clock)
+	second := NewSeededGenerator(42, clock)
+
+	assert.Equal(first.NewSecureID(), second.NewSecureID())
+	assert.Equal(first.NewDigitCode(8), second.NewDigitCode(8))
+	assert.Equal(first.NewActivationCode(), second.NewActivationCode())
+	assert.Equal(first.NewCode(TicketCodeFormat), second.NewCode(TicketCodeFormat))
+	assert.Equal(first.NewULID(), second.NewULID())
+	assert.Equal(first.NewUUIDv7(), second.NewUUIDv7())
+
+	other := NewSeededGenerator(43, clock)
+	assert.NotEqual(first.NewSecureID(), other.NewSecureID())
+
+	assert.True(TicketCodeFormat.Validate(first.NewCode(TicketCodeFormat)))
+	assert.True(ValidateActivationCode(first.NewActivationCode()))
+
+}
+
+func TestGeneratorClock(t *testing.T) {
+
+	assert := assert.New(t)
+
+	now := time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC)
+	gen := NewSeededGenerator(1, FixedClock(now))
+
+	first := gen.NewULID()
+	second := gen.NewULID()
+	assert.True(second > first)
+
+	ts, err := ULIDTime(first)
+	assert.NoError(err)
+	assert.Equal(now, ts)
+
+	u := gen.NewUUIDv7()
+	assert.Equal(UUIDVersion7, u.Version())
+	assert.Equal("01726fc0", u.String()[:8])
+
+}
+
+func TestSetDefaultGenerator(t *testing.T) {
+
+	assert := assert.New(t)
+
+	clock := FixedClock(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+
+	previous := SetDefaultGenerator(NewSeededGenerator(7, clock))
+	id := NewSecureID()
+	code := NewActivationCode()
+	n := Rand.Intn(1000)
+
+	SetDefaultGenerator(NewSeededGenerator(7, clock))
+	assert.Equal(id, NewSecureID())
+	assert.Equal(code, NewActivationCode())
+	assert.Equal(n, Rand.Intn(1000))
+
+	SetDefaultGenerator(previous)
+	assert.NotEqual(id, NewSecureID())
+
+}
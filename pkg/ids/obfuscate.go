@@ -0,0 +1,252 @@
+package ids
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// publicIDAlphabet leaves out characters that are easily confused in URLs and
+// print: 0, 1, I, O and l.
+const publicIDAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ErrInvalidPublicID is returned when decoding a malformed public id, or one
+// that was encoded for a different entity type.
+var ErrInvalidPublicID = errors.New("invalid public id")
+
+/*
+Obfuscator converts integer ids, such as snowflakes or sequential keys, into
+short alphanumeric public ids and back. The encoding is keyed by a secret and
+an entity type, so the same id encodes differently for users and tickets and a
+public id for one type won't decode as another.
+
+Obfuscation hides how many records exist and stops ids from being guessed
+sequentially, but it's not encryption or authorization. Use signed tokens when
+possession of an id must grant access.
+*/
+type Obfuscator struct {
+	entityType string
+	minLength  int
+	key        []byte
+	url        *obfuscation
+	code       *obfuscation
+}
+
+// obfuscation holds the salted alphabets for one output alphabet.
+type obfuscation struct {
+	base      string   //salted alphabet used for lottery characters
+	guard     byte     //marks the start of padding
+	alphabets []string //digit alphabet for each lottery character
+}
+
+/*
+NewObfuscator returns an obfuscator for an entity type. Public ids shorter
+than minLength are padded.
+*/
+func NewObfuscator(secret string, entityType string, minLength int) *Obfuscator {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(entityType))
+	key := mac.Sum(nil)
+
+	return &Obfuscator{
+		entityType: entityType,
+		minLength:  minLength,
+		key:        key,
+		url:        newObfuscation(key, "url", publicIDAlphabet),
+		code:       newObfuscation(key, "code", crockfordAlphabet),
+	}
+
+}
+
+// EntityType returns the entity type the obfuscator encodes ids for.
+func (o *Obfuscator) EntityType() string {
+	return o.entityType
+}
+
+/*
+Encode converts a non-negative id into a URL safe public id.
+*/
+func (o *Obfuscator) Encode(id int64) (string, error) {
+
+	if id < 0 {
+		return "", ErrInvalidPublicID
+	}
+
+	return o.url.encode(o.lottery(id), uint64(id), o.minLength), nil
+
+}
+
+/*
+Decode converts a public id back into the id it was encoded from.
+*/
+func (o *Obfuscator) Decode(publicID string) (int64, error) {
+	return o.decode(o.url, publicID)
+}
+
+/*
+Code converts a non-negative id into a human friendly code for printed
+tickets and receipts. Like codes from CodeFormat, it's made of Crockford
+base32 characters followed by a check character. Use a CodeFormat with the
+desired grouping to display it.
+*/
+func (o *Obfuscator) Code(id int64) (string, error) {
+
+	if id < 0 {
+		return "", ErrInvalidPublicID
+	}
+
+	code := o.code.encode(o.lottery(id), uint64(id), o.minLength)
+
+	return code + string(crockfordCheckAlphabet[checkValue(code)]), nil
+
+}
+
+/*
+ParseCode converts user input back into the id a code was generated from. It
+tolerates the same typing variations as CodeFormat.Parse.
+*/
+func (o *Obfuscator) ParseCode(input string) (int64, error) {
+
+	code, err := parseChecked(normalizeCode(input, defaultCodeSeparator))
+	if err == ErrInvalidCode {
+		return 0, ErrInvalidPublicID
+	} else if err != nil {
+		return 0, err
+	}
+
+	return o.decode(o.code, code[:len(code)-1])
+
+}
+
+// decode reverses encode and re-encodes the result, so that public ids with
+// altered characters, or from another entity type, are rejected.
+func (o *Obfuscator) decode(enc *obfuscation, publicID string) (int64, error) {
+
+	if len(publicID) < 2 {
+		return 0, ErrInvalidPublicID
+	}
+
+	lottery := strings.IndexByte(enc.base, publicID[0])
+	if lottery < 0 {
+		return 0, ErrInvalidPublicID
+	}
+
+	digits := publicID[1:]
+	if guard := strings.IndexByte(digits, enc.guard); guard >= 0 {
+		digits = digits[:guard]
+	}
+
+	if digits == "" {
+		return 0, ErrInvalidPublicID
+	}
+
+	alphabet := enc.alphabets[lottery]
+	radix := uint64(len(alphabet))
+	var id uint64
+
+	for i := 0; i < len(digits); i++ {
+		digit := strings.IndexByte(alphabet, digits[i])
+		if digit < 0 || id > (1<<63-1-uint64(digit))/radix {
+			return 0, ErrInvalidPublicID
+		}
+		id = id*radix + uint64(digit)
+	}
+
+	if enc.encode(o.lottery(int64(id)), id, o.minLength) != publicID {
+		return 0, ErrInvalidPublicID
+	}
+
+	return int64(id), nil
+
+}
+
+// lottery picks the salted alphabet used for an id.
+func (o *Obfuscator) lottery(id int64) uint32 {
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(id))
+
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write(buf[:])
+
+	return binary.BigEndian.Uint32(mac.Sum(nil))
+
+}
+
+func newObfuscation(key []byte, context string, alphabet string) *obfuscation {
+
+	salted := keyedShuffle(alphabet, key, context)
+
+	enc := &obfuscation{
+		base:      salted[1:],
+		guard:     salted[0],
+		alphabets: make([]string, len(salted)-1),
+	}
+
+	for i := range enc.alphabets {
+		enc.alphabets[i] = keyedShuffle(enc.base, key, context+string(enc.base[i]))
+	}
+
+	return enc
+
+}
+
+// encode writes the lottery character followed by the id in the lottery's
+// alphabet, then pads with the guard and filler characters.
+func (enc *obfuscation) encode(salt uint32, id uint64, minLength int) string {
+
+	lottery := int(salt % uint32(len(enc.base)))
+	alphabet := enc.alphabets[lottery]
+	radix := uint64(len(alphabet))
+
+	digits := make([]byte, 0, 13)
+	for {
+		digits = append(digits, alphabet[id%radix])
+		id /= radix
+		if id == 0 {
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteByte(enc.base[lottery])
+	for i := len(digits) - 1; i >= 0; i-- {
+		b.WriteByte(digits[i])
+	}
+
+	if b.Len() < minLength {
+		b.WriteByte(enc.guard)
+		for i := 0; b.Len() < minLength; i++ {
+			b.WriteByte(alphabet[(lottery+i)%len(alphabet)])
+		}
+	}
+
+	return b.String()
+
+}
+
+// keyedShuffle deterministically shuffles an alphabet with a Fisher-Yates
+// shuffle driven by an HMAC keystream.
+func keyedShuffle(alphabet string, key []byte, context string) string {
+
+	result := []byte(alphabet)
+
+	var stream []byte
+	for counter := uint32(0); len(stream) < 4*len(result); counter++ {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(context))
+		binary.Write(mac, binary.BigEndian, counter)
+		stream = mac.Sum(stream)
+	}
+
+	for i := len(result) - 1; i > 0; i-- {
+		j := int(binary.BigEndian.Uint32(stream[4*i:]) % uint32(i+1))
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+
+}
@@ -0,0 +1,121 @@
+package ids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObfuscator(t *testing.T) {
+
+	assert := assert.New(t)
+
+	o := NewObfuscator("secret", "ticket", 6)
+
+	for _, id := range []int64{0, 1, 2, 57, 58, 1234, 1 << 40, 1<<63 - 1} {
+		publicID, err := o.Encode(id)
+		assert.NoError(err)
+		assert.True(len(publicID) >= 6)
+		for _, c := range publicID {
+			assert.Contains(publicIDAlphabet, string(c))
+		}
+
+		decoded, err := o.Decode(publicID)
+		assert.NoError(err)
+		assert.Equal(id, decoded)
+	}
+
+	first, _ := o.Encode(1)
+	second, _ := o.Encode(2)
+	assert.NotEqual(first[:3], second[:3])
+
+	_, err := o.Encode(-1)
+	assert.Equal(ErrInvalidPublicID, err)
+
+	_, err = o.Decode("")
+	assert.Equal(ErrInvalidPublicID, err)
+
+	_, err = o.Decode("0000")
+	assert.Equal(ErrInvalidPublicID, err)
+
+}
+
+func TestObfuscatorEntityTypes(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tickets := NewObfuscator("secret", "ticket", 0)
+	users := NewObfuscator("secret", "user", 0)
+	otherSecret := NewObfuscator("other", "ticket", 0)
+
+	rejected := 0
+	for id := int64(1); id <= 100; id++ {
+		publicID, _ := tickets.Encode(id)
+		userID, _ := users.Encode(id)
+		assert.NotEqual(publicID, userID)
+		if decoded, err := users.Decode(publicID); err != nil || decoded != id {
+			rejected++
+		}
+		if _, err := otherSecret.Decode(publicID); err != nil {
+			rejected++
+		}
+	}
+
+	assert.True(rejected > 190)
+
+}
+
+func TestObfuscatorCode(t *testing.T) {
+
+	assert := assert.New(t)
+
+	o := NewObfuscator("secret", "ticket", 8)
+
+	code, err := o.Code(987654321)
+	assert.NoError(err)
+	assert.Len(code, 9)
+
+	id, err := o.ParseCode(code)
+	assert.NoError(err)
+	assert.Equal(int64(987654321), id)
+
+	display := CodeFormat{GroupSize: 3}.Display(code)
+	id, err = o.ParseCode(" " + display + " ")
+	assert.NoError(err)
+	assert.Equal(int64(987654321), id)
+
+	id, err = o.ParseCode(toLowerAmbiguous(code))
+	assert.NoError(err)
+	assert.Equal(int64(987654321), id)
+
+	altered := []byte(code)
+	if altered[2] == 'A' {
+		altered[2] = 'B'
+	} else {
+		altered[2] = 'A'
+	}
+	_, err = o.ParseCode(string(altered))
+	assert.Error(err)
+
+}
+
+// toLowerAmbiguous lower cases a code and types the digits 0 and 1 as letters.
+func toLowerAmbiguous(code string) string {
+
+	result := []byte(code)
+	for i, c := range result {
+		switch c {
+		case '0':
+			result[i] = 'o'
+		case '1':
+			result[i] = 'l'
+		default:
+			if c >= 'A' && c <= 'Z' {
+				result[i] = c + 'a' - 'A'
+			}
+		}
+	}
+
+	return string(result)
+
+}
@@ -0,0 +1,285 @@
+package ids
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultSlugMaxLength is the default maximum slug length, suffix included.
+const DefaultSlugMaxLength = 64
+
+// Slug errors.
+var (
+	ErrEmptySlug         = errors.New("text produces an empty slug")
+	ErrInvalidIdentifier = errors.New("invalid table or column name")
+)
+
+// SlugQuerier runs the queries that check slugs are unused. *sql.Tx
+// implements it; wrap one with sqltrace.Traced to trace the queries.
+type SlugQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// DefaultReservedSlugs lists slugs that would collide with application routes.
+var DefaultReservedSlugs = []string{
+	"admin", "api", "assets", "edit", "help", "login", "logout", "new",
+	"search", "settings", "signup", "static", "www",
+}
+
+// DefaultSlugOptions are the options used by the package level slug functions.
+var DefaultSlugOptions = SlugOptions{
+	MaxLength: DefaultSlugMaxLength,
+	Reserved:  DefaultReservedSlugs,
+}
+
+// identifierPattern matches table and column names that are safe to splice
+// into SQL, optionally qualified with a schema name.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// transliterations covers letters that don't decompose into an ASCII base
+// letter and a combining mark.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th", 'ł': "l",
+	'Ł': "l", 'ı': "i", '&': " and ",
+
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// SlugOptions controls slug generation.
+type SlugOptions struct {
+	MaxLength int      // maximum length including any uniqueness suffix, zero for the default
+	Reserved  []string // slugs that are never generated
+}
+
+/*
+Slugify converts text into a URL slug using the default options.
+*/
+func Slugify(text string) string {
+	return DefaultSlugOptions.Slugify(text)
+}
+
+/*
+UniqueSlugWithTx generates a slug for text using the default options that
+isn't already used in the given table and column.
+*/
+func UniqueSlugWithTx(tx SlugQuerier, table string, column string, text string) (string, error) {
+	return DefaultSlugOptions.UniqueWithTx(tx, table, column, text)
+}
+
+/*
+Slugify converts text into a lower case URL slug made of ASCII letters and
+digits separated by hyphens. Accented letters lose their accents and a few
+other letters, including Cyrillic, are transliterated. Anything else is
+dropped. Long slugs are cut at a word boundary where possible. The result can
+be empty or reserved; UniqueWithTx handles both.
+*/
+func (opts SlugOptions) Slugify(text string) string {
+
+	decomposed, _, err := transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn))), text)
+	if err != nil {
+		decomposed = text
+	}
+
+	var b strings.Builder
+	pendingSeparator := false
+
+	for _, r := range strings.ToLower(decomposed) {
+		if tr, ok := transliterations[r]; ok {
+			for _, c := range tr {
+				pendingSeparator = writeSlugRune(&b, c, pendingSeparator)
+			}
+			continue
+		}
+		pendingSeparator = writeSlugRune(&b, r, pendingSeparator)
+	}
+
+	return truncateSlug(b.String(), opts.maxLength())
+
+}
+
+/*
+UniqueWithTx generates a slug for text that isn't reserved and isn't already
+used in the given table and column, appending -2, -3 and so on as needed.
+The check runs within the transaction, so callers should insert the slug in
+the same transaction, ideally backed by a unique index. Pass
+sqltrace.Traced(tx, sqltrace.TargetPrimary) to trace the queries.
+*/
+func (opts SlugOptions) UniqueWithTx(tx SlugQuerier, table string, column string, text string) (string, error) {
+
+	if !identifierPattern.MatchString(table) || !identifierPattern.MatchString(column) {
+		return "", ErrInvalidIdentifier
+	}
+
+	base := opts.Slugify(text)
+	if base == "" {
+		return "", ErrEmptySlug
+	}
+
+	taken := make(map[string]bool)
+	for _, reserved := range opts.Reserved {
+		taken[reserved] = true
+	}
+
+	//suffixed slugs may shorten the base to fit, so read every prefix a
+	//suffix up to -999 can produce, and any longer one it turns out to need
+	queried := make(map[string]bool)
+	prefixes := []string{base}
+	for digits := 1; digits <= 3; digits++ {
+		prefixes = append(prefixes, opts.suffixPrefix(base, digits))
+	}
+
+	for {
+		var pending []string
+		for _, prefix := range prefixes {
+			if !queried[prefix] {
+				queried[prefix] = true
+				pending = append(pending, prefix)
+			}
+		}
+
+		if len(pending) > 0 {
+			if err := queryTakenSlugs(tx, table, column, base, pending, taken); err != nil {
+				return "", err
+			}
+		}
+
+		candidate, prefix := opts.resolveUnique(base, taken)
+		if queried[prefix] {
+			return candidate, nil
+		}
+
+		prefixes = []string{prefix}
+	}
+
+}
+
+// queryTakenSlugs marks the base and every slug starting with one of the
+// prefixes and a hyphen as taken.
+func queryTakenSlugs(tx SlugQuerier, table string, column string, base string, prefixes []string, taken map[string]bool) error {
+
+	query := "SELECT " + column + " FROM " + table + " WHERE " + column + " = $1"
+	args := []interface{}{base}
+
+	for _, prefix := range prefixes {
+		args = append(args, escapeLike(prefix)+"-%")
+		query += " OR " + column + " LIKE $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var existing string
+		if err = rows.Scan(&existing); err != nil {
+			return err
+		}
+		taken[existing] = true
+	}
+
+	return rows.Err()
+
+}
+
+/*
+resolveUnique returns base, or base with the lowest free numeric suffix,
+along with the prefix the suffix was appended to. The base is shortened if
+needed so the suffix fits the length limit.
+*/
+func (opts SlugOptions) resolveUnique(base string, taken map[string]bool) (string, string) {
+
+	if !taken[base] {
+		return base, base
+	}
+
+	for n := 2; ; n++ {
+		suffix := strconv.Itoa(n)
+		prefix := opts.suffixPrefix(base, len(suffix))
+		candidate := prefix + "-" + suffix
+		if !taken[candidate] {
+			return candidate, prefix
+		}
+	}
+
+}
+
+// suffixPrefix returns the base shortened to leave room for a hyphen and a
+// numeric suffix with the given number of digits.
+func (opts SlugOptions) suffixPrefix(base string, digits int) string {
+	return truncateSlug(base, opts.maxLength()-digits-1)
+}
+
+func (opts SlugOptions) maxLength() int {
+
+	if opts.MaxLength <= 0 {
+		return DefaultSlugMaxLength
+	}
+
+	return opts.MaxLength
+
+}
+
+// writeSlugRune writes ASCII letters and digits, collapsing everything else
+// into a single separator between words.
+func writeSlugRune(b *strings.Builder, r rune, pendingSeparator bool) bool {
+
+	if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+		if pendingSeparator && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+		return false
+	}
+
+	if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '\'' || r == '’' {
+		//untransliterated letters and apostrophes don't split words
+		return pendingSeparator
+	}
+
+	return true
+
+}
+
+// truncateSlug cuts a slug to maxLength, preferring the last word boundary.
+func truncateSlug(slug string, maxLength int) string {
+
+	if len(slug) <= maxLength {
+		return slug
+	}
+
+	if maxLength <= 0 {
+		return ""
+	}
+
+	cut := slug[:maxLength]
+	if slug[maxLength] != '-' {
+		if idx := strings.LastIndexByte(cut, '-'); idx > 0 {
+			cut = cut[:idx]
+		}
+	}
+
+	return strings.TrimRight(cut, "-")
+
+}
+
+// escapeLike escapes LIKE wildcards. Slugs never contain them, but the escape
+// keeps the query correct if the rules change.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
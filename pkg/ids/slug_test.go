@@ -0,0 +1,250 @@
+package ids
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal("the-phantom-of-the-opera", Slugify("The Phantom of the Opera"))
+	assert.Equal("les-miserables", Slugify("  Les Misérables!  "))
+	assert.Equal("strasse-and-platz", Slugify("Straße & Platz"))
+	assert.Equal("cafe-noir-2019", Slugify("Café—Noir (2019)"))
+	assert.Equal("dont-stop", Slugify("Don't Stop"))
+	assert.Equal("chaika", Slugify("Чайка"))
+	assert.Equal("office", Slugify("ｏｆｆｉｃｅ"))
+	assert.Equal("", Slugify("!!!"))
+
+	opts := SlugOptions{MaxLength: 12}
+	assert.Equal("a-midsummer", opts.Slugify("A Midsummer Night's Dream"))
+	assert.Equal("abcdefghijkl", opts.Slugify("abcdefghijklmnop"))
+
+}
+
+func TestResolveUniqueSlug(t *testing.T) {
+
+	assert := assert.New(t)
+
+	opts := SlugOptions{MaxLength: 10, Reserved: DefaultReservedSlugs}
+
+	candidate, prefix := opts.resolveUnique("hamlet", map[string]bool{"hamlet-2": true})
+	assert.Equal("hamlet", candidate)
+	assert.Equal("hamlet", prefix)
+
+	candidate, _ = opts.resolveUnique("hamlet", map[string]bool{"hamlet": true})
+	assert.Equal("hamlet-2", candidate)
+
+	candidate, _ = opts.resolveUnique("hamlet", map[string]bool{"hamlet": true, "hamlet-2": true, "hamlet-3": true})
+	assert.Equal("hamlet-4", candidate)
+
+	taken := map[string]bool{"abcdefghij": true}
+	for n := 2; n <= 9; n++ {
+		taken["abcdefgh-"+strconv.Itoa(n)] = true
+	}
+	candidate, prefix = opts.resolveUnique("abcdefghij", taken)
+	assert.Equal("abcdefg-10", candidate)
+	assert.Equal("abcdefg", prefix)
+
+}
+
+func TestUniqueSlugQuery(t *testing.T) {
+
+	assert := assert.New(t)
+
+	opts := SlugOptions{MaxLength: 10, Reserved: DefaultReservedSlugs}
+
+	//the suffixed slugs were stored with shortened bases
+	existing := []string{"abcdefghij", "abcdefgh-2", "abcdefgh-3", "abcdefg-10", "unrelated"}
+
+	tx, queries := beginSlugTx(t, existing)
+	slug, err := opts.UniqueWithTx(tx, "productions", "slug", "ABCDEFGHIJ")
+	assert.NoError(err)
+	assert.Equal("abcdefgh-4", slug)
+	assert.Equal(1, *queries)
+	tx.Rollback()
+
+	for n := 4; n <= 9; n++ {
+		existing = append(existing, "abcdefgh-"+strconv.Itoa(n))
+	}
+
+	tx, _ = beginSlugTx(t, existing)
+	slug, err = opts.UniqueWithTx(tx, "productions", "slug", "abcdefghij")
+	assert.NoError(err)
+	assert.Equal("abcdefg-11", slug)
+	tx.Rollback()
+
+	//a suffix longer than the first query anticipated needs another query
+	short := SlugOptions{MaxLength: 6}
+	existing = []string{"abcdef"}
+	for n := 2; n <= 999; n++ {
+		existing = append(existing, short.suffixPrefix("abcdef", len(strconv.Itoa(n)))+"-"+strconv.Itoa(n))
+	}
+	existing = append(existing, "a-1000")
+
+	tx, queries = beginSlugTx(t, existing)
+	slug, err = short.UniqueWithTx(tx, "productions", "slug", "abcdef")
+	assert.NoError(err)
+	assert.Equal("a-1001", slug)
+	assert.Equal(2, *queries)
+	tx.Rollback()
+
+	tx, _ = beginSlugTx(t, []string{"hamlet"})
+	slug, err = opts.UniqueWithTx(tx, "productions", "slug", "Admin")
+	assert.NoError(err)
+	assert.Equal("admin-2", slug)
+	tx.Rollback()
+
+}
+
+func TestUniqueSlugIdentifiers(t *testing.T) {
+
+	assert := assert.New(t)
+
+	_, err := UniqueSlugWithTx(nil, "productions; drop table users", "slug", "Hamlet")
+	assert.Equal(ErrInvalidIdentifier, err)
+
+	_, err = UniqueSlugWithTx(nil, "productions", "slug--", "Hamlet")
+	assert.Equal(ErrInvalidIdentifier, err)
+
+	_, err = UniqueSlugWithTx(nil, "public.productions", "slug", "???")
+	assert.Equal(ErrEmptySlug, err)
+
+	assert.True(identifierPattern.MatchString("public.productions"))
+	assert.False(strings.Contains(escapeLike("a_b%"), "_b%"))
+
+}
+
+// slugDriver serves slug queries from an in memory column, evaluating the
+// exact match and LIKE prefix arguments UniqueWithTx sends.
+type slugDriver struct {
+	existing []string
+	queries  int
+}
+
+var slugDrivers = make(map[string]*slugDriver)
+
+func init() {
+	sql.Register("slugtest", slugConnector{})
+}
+
+// beginSlugTx opens a transaction against an in memory slug column and
+// returns a counter of the queries run against it.
+func beginSlugTx(t *testing.T, existing []string) (*sql.Tx, *int) {
+
+	name := t.Name() + strconv.Itoa(len(slugDrivers))
+	slugDrivers[name] = &slugDriver{existing: existing}
+
+	db, err := sql.Open("slugtest", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tx, &slugDrivers[name].queries
+
+}
+
+type slugConnector struct{}
+
+func (slugConnector) Open(name string) (driver.Conn, error) {
+	return slugDrivers[name], nil
+}
+
+func (d *slugDriver) Prepare(query string) (driver.Stmt, error) {
+	return slugStmt{d}, nil
+}
+
+func (d *slugDriver) Close() error {
+	return nil
+}
+
+func (d *slugDriver) Begin() (driver.Tx, error) {
+	return d, nil
+}
+
+func (d *slugDriver) Commit() error {
+	return nil
+}
+
+func (d *slugDriver) Rollback() error {
+	return nil
+}
+
+type slugStmt struct {
+	d *slugDriver
+}
+
+func (stmt slugStmt) Close() error {
+	return nil
+}
+
+func (stmt slugStmt) NumInput() int {
+	return -1
+}
+
+func (stmt slugStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (stmt slugStmt) Query(args []driver.Value) (driver.Rows, error) {
+
+	stmt.d.queries++
+
+	unescape := strings.NewReplacer(`\\`, `\`, `\%`, "%", `\_`, "_")
+
+	var matches []string
+	for _, value := range stmt.d.existing {
+		if value == args[0].(string) {
+			matches = append(matches, value)
+			continue
+		}
+		for _, arg := range args[1:] {
+			if strings.HasPrefix(value, unescape.Replace(strings.TrimSuffix(arg.(string), "%"))) {
+				matches = append(matches, value)
+				break
+			}
+		}
+	}
+
+	return &slugRows{values: matches}, nil
+
+}
+
+type slugRows struct {
+	values []string
+}
+
+func (rows *slugRows) Columns() []string {
+	return []string{"slug"}
+}
+
+func (rows *slugRows) Close() error {
+	return nil
+}
+
+func (rows *slugRows) Next(dest []driver.Value) error {
+
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+
+	dest[0] = rows.values[0]
+	rows.values = rows.values[1:]
+
+	return nil
+
+}
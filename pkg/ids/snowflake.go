@@ -0,0 +1,246 @@
+package ids
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snowflake layout constants. A snowflake is a positive 63 bit integer made of
+// a 41 bit millisecond timestamp, a 10 bit node id and a 12 bit sequence.
+const (
+	SnowflakeNodeBits     = 10
+	SnowflakeSequenceBits = 12
+	SnowflakeMaxNode      = 1<<SnowflakeNodeBits - 1
+
+	snowflakeMaxSequence = 1<<SnowflakeSequenceBits - 1
+	snowflakeTimeShift   = SnowflakeNodeBits + SnowflakeSequenceBits
+
+	// MaxClockRollback is the largest backwards clock jump a snowflake
+	// generator absorbs. Larger jumps cause generation to fail rather than
+	// risk duplicate ids.
+	MaxClockRollback = 5 * time.Second
+
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// SnowflakeEpoch is the zero point of snowflake timestamps.
+var SnowflakeEpoch = time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake errors.
+var (
+	ErrInvalidNode       = errors.New("snowflake node id out of range")
+	ErrNodeNotConfigured = errors.New("snowflake node id not configured")
+	ErrClockRollback     = errors.New("clock moved backwards beyond the snowflake tolerance")
+	ErrInvalidSnowflake  = errors.New("invalid snowflake")
+)
+
+var (
+	defaultSnowflakeLock sync.RWMutex
+	defaultSnowflake     *SnowflakeGenerator
+	disabledSnowflake    error
+)
+
+// Snowflake is a 64 bit, time sortable id for high volume tables.
+type Snowflake int64
+
+// SnowflakeGenerator generates snowflakes for a single node. Each process
+// generating snowflakes concurrently must use a different node id.
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	lastTime int64
+	sequence int64
+	now      func() time.Time
+}
+
+// NewSnowflakeGenerator returns a generator for the given node id.
+func NewSnowflakeGenerator(node int) (*SnowflakeGenerator, error) {
+
+	if node < 0 || node > SnowflakeMaxNode {
+		return nil, ErrInvalidNode
+	}
+
+	return &SnowflakeGenerator{node: int64(node), now: time.Now}, nil
+
+}
+
+/*
+Next generates the next snowflake. If the clock moves backwards by less than
+MaxClockRollback, the generator keeps counting from the last timestamp it
+issued, so ids stay unique and ordered. When the sequence for a millisecond is
+exhausted, it moves on to the next millisecond.
+*/
+func (gen *SnowflakeGenerator) Next() (Snowflake, error) {
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	now := gen.now().Sub(SnowflakeEpoch).Nanoseconds() / int64(time.Millisecond)
+
+	if now < gen.lastTime && time.Duration(gen.lastTime-now)*time.Millisecond > MaxClockRollback {
+		return 0, ErrClockRollback
+	}
+
+	if now > gen.lastTime {
+		gen.lastTime = now
+		gen.sequence = 0
+	} else {
+		gen.sequence++
+		if gen.sequence > snowflakeMaxSequence {
+			gen.lastTime++
+			gen.sequence = 0
+		}
+	}
+
+	return Snowflake(gen.lastTime<<snowflakeTimeShift | gen.node<<SnowflakeSequenceBits | gen.sequence), nil
+
+}
+
+// ConfigureSnowflake sets the node id of the default snowflake generator.
+func ConfigureSnowflake(node int) error {
+
+	gen, err := NewSnowflakeGenerator(node)
+	if err != nil {
+		return err
+	}
+
+	defaultSnowflakeLock.Lock()
+	defer defaultSnowflakeLock.Unlock()
+
+	defaultSnowflake = gen
+	disabledSnowflake = nil
+
+	return nil
+
+}
+
+/*
+DisableSnowflake stops the default snowflake generator, so NewSnowflake
+returns the given error until a node id is configured again. It's used when
+the process can no longer be sure its node id is its own, such as when a
+node lease is lost.
+*/
+func DisableSnowflake(reason error) {
+
+	defaultSnowflakeLock.Lock()
+	defer defaultSnowflakeLock.Unlock()
+
+	defaultSnowflake = nil
+	disabledSnowflake = reason
+
+}
+
+/*
+NewSnowflake generates a snowflake with the default generator, which must be
+configured with a node id first.
+*/
+func NewSnowflake() (Snowflake, error) {
+
+	defaultSnowflakeLock.RLock()
+	gen, disabled := defaultSnowflake, disabledSnowflake
+	defaultSnowflakeLock.RUnlock()
+
+	if disabled != nil {
+		return 0, disabled
+	}
+
+	if gen == nil {
+		return 0, ErrNodeNotConfigured
+	}
+
+	return gen.Next()
+
+}
+
+// Time returns the time the snowflake was generated.
+func (id Snowflake) Time() time.Time {
+	ms := int64(id) >> snowflakeTimeShift
+	return SnowflakeEpoch.Add(time.Duration(ms) * time.Millisecond)
+}
+
+// Node returns the id of the node that generated the snowflake.
+func (id Snowflake) Node() int {
+	return int(int64(id) >> SnowflakeSequenceBits & SnowflakeMaxNode)
+}
+
+// String returns the decimal form of the snowflake.
+func (id Snowflake) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// Base32 returns the snowflake in Crockford's base32, which sorts in the same
+// order as the numeric form.
+func (id Snowflake) Base32() string {
+	return encodeRadix(uint64(id), crockfordAlphabet, 13)
+}
+
+// Base62 returns the snowflake in base62, which sorts in the same order as the
+// numeric form when compared bytewise.
+func (id Snowflake) Base62() string {
+	return encodeRadix(uint64(id), base62Alphabet, 11)
+}
+
+// ParseSnowflake parses the decimal form of a snowflake.
+func ParseSnowflake(value string) (Snowflake, error) {
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, ErrInvalidSnowflake
+	}
+
+	return Snowflake(parsed), nil
+
+}
+
+// ParseSnowflakeBase32 parses the base32 form of a snowflake.
+func ParseSnowflakeBase32(value string) (Snowflake, error) {
+	return decodeRadix(strings.ToUpper(value), crockfordAlphabet)
+}
+
+// ParseSnowflakeBase62 parses the base62 form of a snowflake.
+func ParseSnowflakeBase62(value string) (Snowflake, error) {
+	return decodeRadix(value, base62Alphabet)
+}
+
+// encodeRadix encodes a value in the given alphabet, left padded with the
+// zero character to a fixed width.
+func encodeRadix(value uint64, alphabet string, width int) string {
+
+	radix := uint64(len(alphabet))
+	result := make([]byte, width)
+
+	for i := width - 1; i >= 0; i-- {
+		result[i] = alphabet[value%radix]
+		value /= radix
+	}
+
+	return string(result)
+
+}
+
+func decodeRadix(value string, alphabet string) (Snowflake, error) {
+
+	if value == "" {
+		return 0, ErrInvalidSnowflake
+	}
+
+	radix := uint64(len(alphabet))
+	var result uint64
+
+	for i := 0; i < len(value); i++ {
+		digit := strings.IndexByte(alphabet, value[i])
+		if digit < 0 {
+			return 0, ErrInvalidSnowflake
+		}
+		if result > (1<<63-1-uint64(digit))/radix {
+			return 0, ErrInvalidSnowflake
+		}
+		result = result*radix + uint64(digit)
+	}
+
+	return Snowflake(result), nil
+
+}
@@ -0,0 +1,144 @@
+package ids
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnowflake(t *testing.T) {
+
+	assert := assert.New(t)
+
+	gen, err := NewSnowflakeGenerator(513)
+	assert.NoError(err)
+
+	before := time.Now().Add(-time.Millisecond)
+
+	id, err := gen.Next()
+	assert.NoError(err)
+	assert.True(id > 0)
+	assert.Equal(513, id.Node())
+	assert.True(id.Time().After(before))
+	assert.True(id.Time().Before(time.Now().Add(time.Millisecond)))
+
+	_, err = NewSnowflakeGenerator(SnowflakeMaxNode + 1)
+	assert.Equal(ErrInvalidNode, err)
+
+	_, err = NewSnowflakeGenerator(-1)
+	assert.Equal(ErrInvalidNode, err)
+
+}
+
+func TestSnowflakeOrdering(t *testing.T) {
+
+	assert := assert.New(t)
+
+	gen, _ := NewSnowflakeGenerator(1)
+
+	var last Snowflake
+	lastBase32, lastBase62 := "", ""
+
+	for i := 0; i < 10000; i++ {
+		id, err := gen.Next()
+		assert.NoError(err)
+		assert.True(id > last)
+		assert.True(id.Base32() > lastBase32)
+		assert.True(id.Base62() > lastBase62)
+		last, lastBase32, lastBase62 = id, id.Base32(), id.Base62()
+	}
+
+}
+
+func TestSnowflakeClockRollback(t *testing.T) {
+
+	assert := assert.New(t)
+
+	now := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	gen, _ := NewSnowflakeGenerator(7)
+	gen.now = func() time.Time { return now }
+
+	first, err := gen.Next()
+	assert.NoError(err)
+
+	now = now.Add(-time.Second)
+
+	second, err := gen.Next()
+	assert.NoError(err)
+	assert.True(second > first)
+
+	now = now.Add(-MaxClockRollback)
+
+	_, err = gen.Next()
+	assert.Equal(ErrClockRollback, err)
+
+}
+
+func TestSnowflakeEncoding(t *testing.T) {
+
+	assert := assert.New(t)
+
+	gen, _ := NewSnowflakeGenerator(42)
+	id, _ := gen.Next()
+
+	assert.Len(id.Base32(), 13)
+	assert.Len(id.Base62(), 11)
+
+	parsed, err := ParseSnowflake(id.String())
+	assert.NoError(err)
+	assert.Equal(id, parsed)
+
+	parsed, err = ParseSnowflakeBase32(id.Base32())
+	assert.NoError(err)
+	assert.Equal(id, parsed)
+
+	parsed, err = ParseSnowflakeBase62(id.Base62())
+	assert.NoError(err)
+	assert.Equal(id, parsed)
+
+	_, err = ParseSnowflake("-12")
+	assert.Equal(ErrInvalidSnowflake, err)
+
+	_, err = ParseSnowflakeBase62("zzzzzzzzzzzz")
+	assert.Equal(ErrInvalidSnowflake, err)
+
+	_, err = ParseSnowflakeBase32("!")
+	assert.Equal(ErrInvalidSnowflake, err)
+
+}
+
+func TestSnowflakeStrategy(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(ConfigureSnowflake(3))
+
+	id, err := NewID(StrategySnowflake)
+	assert.NoError(err)
+
+	parsed, err := ParseSnowflake(id)
+	assert.NoError(err)
+	assert.Equal(3, parsed.Node())
+
+}
+
+func TestDisableSnowflake(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(ConfigureSnowflake(4))
+
+	lost := errors.New("lease lost")
+	DisableSnowflake(lost)
+
+	_, err := NewSnowflake()
+	assert.Equal(lost, err)
+
+	assert.NoError(ConfigureSnowflake(4))
+	_, err = NewSnowflake()
+	assert.NoError(err)
+
+}
@@ -0,0 +1,57 @@
+package ids
+
+import "errors"
+
+// Strategy names a technique for generating entity ids.
+type Strategy string
+
+// Strategy constants. All but snowflake produce 26 character ids.
+const (
+	StrategySecure    Strategy = "secure"    //random, see NewSecureID
+	StrategyULID      Strategy = "ulid"      //time sortable, see NewULID
+	StrategyUUID      Strategy = "uuid"      //base32 encoded UUID, see NewBase32UUID
+	StrategySnowflake Strategy = "snowflake" //decimal 64 bit id for BIGINT columns, see NewSnowflake
+
+	StrategyDefault = StrategySecure
+)
+
+// ErrUnknownStrategy is returned for unrecognized id strategies.
+var ErrUnknownStrategy = errors.New("unknown id strategy")
+
+/*
+NewID generates an id using the given strategy. An empty strategy uses the
+default.
+*/
+func NewID(strategy Strategy) (string, error) {
+
+	switch strategy {
+	case "", StrategySecure:
+		return NewSecureID(), nil
+	case StrategyULID:
+		return NewULID(), nil
+	case StrategyUUID:
+		return NewBase32UUID(), nil
+	case StrategySnowflake:
+		id, err := NewSnowflake()
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+
+	return "", ErrUnknownStrategy
+
+}
+
+// Valid returns true if the strategy is recognized. The empty strategy is
+// valid and means the default.
+func (strategy Strategy) Valid() bool {
+
+	switch strategy {
+	case "", StrategySecure, StrategyULID, StrategyUUID, StrategySnowflake:
+		return true
+	}
+
+	return false
+
+}
@@ -0,0 +1,100 @@
+package ids
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	ulidLength        = 26
+	ulidEntropyLength = 10
+	ulidMaxTime       = 1<<48 - 1
+
+	// crockfordAlphabet is Crockford's base32 alphabet, which sorts in the same
+	// order as the values it encodes.
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// ErrInvalidULID is returned when parsing a malformed ULID.
+var ErrInvalidULID = errors.New("invalid ulid")
+
+/*
+NewULID generates a 26 character, time sortable identifier made of a 48 bit
+millisecond timestamp followed by 80 random bits, encoded in Crockford's
+base32. IDs generated within the same millisecond increment the random
+component so they still sort in generation order. Like NewSecureID, the
+result fits CHAR(26) id columns.
+*/
+func NewULID() string {
+	return DefaultGenerator().NewULID()
+}
+
+// ULIDTime returns the timestamp component of a ULID.
+func ULIDTime(id string) (time.Time, error) {
+
+	if len(id) != ulidLength {
+		return time.Time{}, ErrInvalidULID
+	}
+
+	var ms uint64
+
+	for _, r := range strings.ToUpper(id[:10]) {
+		idx := strings.IndexRune(crockfordAlphabet, r)
+		if idx < 0 {
+			return time.Time{}, ErrInvalidULID
+		}
+		ms = ms<<5 | uint64(idx)
+	}
+
+	if ms > ulidMaxTime {
+		return time.Time{}, ErrInvalidULID
+	}
+
+	return time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC(), nil
+
+}
+
+// incrementEntropy adds one to the random component, returning false if it
+// overflowed.
+func incrementEntropy(entropy *[ulidEntropyLength]byte) bool {
+
+	for i := ulidEntropyLength - 1; i >= 0; i-- {
+		entropy[i]++
+		if entropy[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// encodeULID encodes the 128 bits of a ULID five bits at a time, starting with
+// the two padding bits in front of the timestamp.
+func encodeULID(ms uint64, entropy [ulidEntropyLength]byte) string {
+
+	var raw [16]byte
+	for i := 0; i < 6; i++ {
+		raw[i] = byte(ms >> uint(40-8*i))
+	}
+	copy(raw[6:], entropy[:])
+
+	result := make([]byte, ulidLength)
+
+	for i := 0; i < ulidLength; i++ {
+		bit := i*5 - 2
+		var value uint
+		for j := 0; j < 5; j++ {
+			value <<= 1
+			pos := bit + j
+			if pos >= 0 && raw[pos/8]&(0x80>>uint(pos%8)) != 0 {
+				value |= 1
+			}
+		}
+		result[i] = crockfordAlphabet[value]
+	}
+
+	return string(result)
+
+}
@@ -0,0 +1,69 @@
+package ids
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULID(t *testing.T) {
+
+	assert := assert.New(t)
+
+	before := time.Now().Add(-time.Millisecond)
+
+	id := NewULID()
+
+	assert.Len(id, 26)
+
+	ts, err := ULIDTime(id)
+	assert.NoError(err)
+	assert.True(ts.After(before))
+	assert.True(ts.Before(time.Now().Add(time.Millisecond)))
+
+}
+
+func TestULIDMonotonic(t *testing.T) {
+
+	generated := make([]string, 10000)
+	for i := range generated {
+		generated[i] = NewULID()
+	}
+
+	assert.True(t, sort.StringsAreSorted(generated), "ulids must sort in generation order")
+
+	for i := 1; i < len(generated); i++ {
+		if generated[i] == generated[i-1] {
+			t.Fatal("ULID collision!")
+		}
+	}
+
+}
+
+func TestEncodeULID(t *testing.T) {
+
+	var max [ulidEntropyLength]byte
+	for i := range max {
+		max[i] = 0xff
+	}
+
+	assert.Equal(t, "00000000000000000000000000", encodeULID(0, [ulidEntropyLength]byte{}))
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(ulidMaxTime, max))
+	assert.Equal(t, "01ARZ3NDEK", encodeULID(1469922850259, max)[:10])
+
+}
+
+func TestIDStrategies(t *testing.T) {
+
+	for _, strategy := range []Strategy{"", StrategySecure, StrategyULID, StrategyUUID} {
+		id, err := NewID(strategy)
+		assert.NoError(t, err)
+		assert.Len(t, id, 26)
+	}
+
+	_, err := NewID("sequence")
+	assert.Equal(t, ErrUnknownStrategy, err)
+
+}
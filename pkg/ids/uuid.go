@@ -1,20 +1,40 @@
 package ids
 
 import (
-	"encoding/base32"
-	"encoding/base64"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/gofrs/uuid"
-	"github.com/production-grid/pgrid-core/pkg/logging"
 )
 
+// UUID versions.
+const (
+	UUIDVersion4 = 4
+	UUIDVersion5 = 5
+	UUIDVersion7 = 7
+)
+
+// ErrInvalidUUID is returned when parsing a malformed UUID.
+var ErrInvalidUUID = errors.New("invalid uuid")
+
+// UUID is a 128 bit universally unique identifier. It can be used directly in
+// `col` tagged entity fields and JSON documents. The nil UUID is stored as
+// NULL and marshaled as an empty string.
+type UUID [16]byte
+
+// NilUUID is the UUID with all bits set to zero.
+var NilUUID UUID
+
+// Predefined namespaces for name based UUIDs, from RFC 4122.
 var (
-	initializerSeeder sync.Once
-	uuidChannel       chan uuid.UUID
+	NamespaceDNS  = MustParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceURL  = MustParseUUID("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceOID  = MustParseUUID("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceX500 = MustParseUUID("6ba7b814-9dad-11d1-80b4-00c04fd430c8")
 )
 
 /*
@@ -25,62 +45,198 @@ type Identified interface {
 }
 
 /*
-NewUUID generates a string encoded UUID in the conventional format.
+NewUUIDv4 generates a random UUID.
 */
-func NewUUID() string {
-	u := NewRawUUID()
-	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+func NewUUIDv4() UUID {
+	return DefaultGenerator().NewUUIDv4()
 }
 
-// doGenerateUuids creates an indefinite supply of V1 UUIDs and puts them into
-// a blocking channel. This was intended to address UUID duplication.
-// NOTE: This may no longer be necessary since replacing the
-// package github.com/satori/go.uuid
-// See: https://github.com/satori/go.uuid/issues/73
-func doGenerateUuids() {
-	for {
-		u, err := uuid.NewV1()
-		if err != nil {
-			logging.Errorf("Failed to generate UUID: %v", err)
-			continue
+/*
+NewUUIDv5 generates a name based UUID. The same namespace and name always
+produce the same UUID.
+*/
+func NewUUIDv5(namespace UUID, name string) UUID {
+
+	hash := sha1.New()
+	hash.Write(namespace[:])
+	hash.Write([]byte(name))
+
+	var u UUID
+	copy(u[:], hash.Sum(nil))
+	u.setVersion(UUIDVersion5)
+
+	return u
+
+}
+
+/*
+NewUUIDv7 generates a time ordered UUID with a 48 bit millisecond timestamp.
+The 12 bits following the version act as a counter for UUIDs generated within
+the same millisecond, so they sort in generation order.
+*/
+func NewUUIDv7() UUID {
+	return DefaultGenerator().NewUUIDv7()
+}
+
+/*
+ParseUUID parses a UUID in canonical form, with or without hyphens.
+*/
+func ParseUUID(value string) (UUID, error) {
+
+	var u UUID
+
+	value = strings.Trim(value, "{}")
+
+	switch len(value) {
+	case 36:
+		if value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+			return u, ErrInvalidUUID
 		}
-		uuidChannel <- u
+		value = strings.Replace(value, "-", "", -1)
+	case 32:
+	default:
+		return u, ErrInvalidUUID
+	}
+
+	if len(value) != 32 {
+		return u, ErrInvalidUUID
 	}
+
+	if _, err := hex.Decode(u[:], []byte(value)); err != nil {
+		return u, ErrInvalidUUID
+	}
+
+	return u, nil
+
 }
 
 /*
-NewRawUUID generates a new raw UUID.
+MustParseUUID is like ParseUUID, but panics if the UUID is invalid.
 */
-func NewRawUUID() uuid.UUID {
+func MustParseUUID(value string) UUID {
+
+	u, err := ParseUUID(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+
+}
+
+// setVersion sets the version and the RFC 4122 variant bits.
+func (u *UUID) setVersion(version byte) {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+// Version returns the UUID version.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsNil returns true for the nil UUID.
+func (u UUID) IsNil() bool {
+	return u == NilUUID
+}
+
+// String returns the UUID in the conventional hyphenated format.
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, which also
+// covers JSON.
+func (u UUID) MarshalText() ([]byte, error) {
 
-	initializerSeeder.Do(func() {
-		time.Sleep(1 * time.Second)
-		uuidChannel = make(chan uuid.UUID, 1000)
-		go doGenerateUuids()
-	})
+	if u.IsNil() {
+		return []byte{}, nil
+	}
 
-	return <-uuidChannel
+	return []byte(u.String()), nil
+
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, which also
+// covers JSON.
+func (u *UUID) UnmarshalText(text []byte) error {
+
+	if len(text) == 0 {
+		*u = NilUUID
+		return nil
+	}
+
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+
+	return nil
 
 }
 
 /*
-NewBase32UUID generates a new Base32 encoded UUID.
+Value implements the valuer interface in order to support sql serialization.
 */
-func NewBase32UUID() string {
+func (u UUID) Value() (driver.Value, error) {
 
-	u := NewRawUUID()
-	rawBytes := u[0:]
-	return strings.Replace(base32.StdEncoding.EncodeToString(rawBytes), "=", "", -1)
+	if u.IsNil() {
+		return nil, nil
+	}
+
+	return u.String(), nil
 
 }
 
 /*
-NewBase64UUID generates a new Base64 encoded UUID.
+Scan implements the scan interface in order to support sql serialization.
 */
-func NewBase64UUID() string {
+func (u *UUID) Scan(src interface{}) error {
+
+	switch dbVal := src.(type) {
+	case nil:
+		*u = NilUUID
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(strings.TrimSpace(dbVal)))
+	case []byte:
+		if len(dbVal) == 16 {
+			copy(u[:], dbVal)
+			return nil
+		}
+		return u.UnmarshalText([]byte(strings.TrimSpace(string(dbVal))))
+	}
 
-	u := NewRawUUID()
-	rawBytes := u[0:]
-	return strings.Replace(base64.StdEncoding.EncodeToString(rawBytes), "=", "", -1)
+	return fmt.Errorf("unable to scan %T into a uuid", src)
 
 }
+
+/*
+NewUUID generates a string encoded random UUID in the conventional format.
+*/
+func NewUUID() string {
+	return NewUUIDv4().String()
+}
+
+/*
+NewRawUUID generates a new random UUID.
+*/
+func NewRawUUID() uuid.UUID {
+	return uuid.UUID(NewUUIDv4())
+}
+
+/*
+NewBase32UUID generates a new Base32 encoded random UUID.
+*/
+func NewBase32UUID() string {
+	return DefaultGenerator().NewBase32UUID()
+}
+
+/*
+NewBase64UUID generates a new Base64 encoded random UUID.
+*/
+func NewBase64UUID() string {
+	return DefaultGenerator().NewBase64UUID()
+}
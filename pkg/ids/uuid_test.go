@@ -1,9 +1,13 @@
 package ids
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestActivationCode(t *testing.T) {
@@ -85,3 +89,64 @@ func TestBase64(t *testing.T) {
 	}
 
 }
+
+func TestUUIDVersions(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal(UUIDVersion4, NewUUIDv4().Version())
+	assert.Equal(UUIDVersion7, NewUUIDv7().Version())
+
+	v5 := NewUUIDv5(NamespaceDNS, "www.example.com")
+	assert.Equal(UUIDVersion5, v5.Version())
+	assert.Equal("2ed6657d-e927-568b-95e1-2665a8aea6a2", v5.String())
+
+}
+
+func TestUUIDv7Ordering(t *testing.T) {
+
+	generated := make([]string, 5000)
+	for i := range generated {
+		generated[i] = NewUUIDv7().String()
+	}
+
+	assert.True(t, sort.StringsAreSorted(generated), "v7 uuids must sort in generation order")
+
+}
+
+func TestUUIDSerialization(t *testing.T) {
+
+	assert := assert.New(t)
+
+	type holder struct {
+		ID UUID `json:"id"`
+	}
+
+	original := holder{ID: NewUUIDv4()}
+
+	content, err := json.Marshal(original)
+	assert.NoError(err)
+	assert.Equal(`{"id":"`+original.ID.String()+`"}`, string(content))
+
+	parsed := holder{}
+	assert.NoError(json.Unmarshal(content, &parsed))
+	assert.Equal(original.ID, parsed.ID)
+
+	value, err := original.ID.Value()
+	assert.NoError(err)
+
+	var scanned UUID
+	assert.NoError(scanned.Scan([]byte(value.(string))))
+	assert.Equal(original.ID, scanned)
+
+	assert.NoError(scanned.Scan(nil))
+	assert.True(scanned.IsNil())
+
+	value, err = scanned.Value()
+	assert.NoError(err)
+	assert.Nil(value)
+
+	_, err = ParseUUID("not-a-uuid")
+	assert.Equal(ErrInvalidUUID, err)
+
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levelResetKeyword clears an explicit level when passed to the level handler.
+const levelResetKeyword = "reset"
+
+// LevelHandler returns an admin HTTP handler for inspecting and changing log
+// levels at runtime. GET returns the effective level of every logger. PUT or
+// POST with the logger and level parameters changes one logger's level.
+// Passing "reset" as the level makes a named logger follow the package level
+// logger again, and omitting the logger restores the configured levels.
+//
+// The handler performs no authorization checks of its own and must only be
+// mounted behind the admin security layer.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(handleLevels)
+}
+
+func handleLevels(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		if err := updateLevel(r.FormValue("logger"), r.FormValue("level")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Levels())
+
+}
+
+func updateLevel(name string, raw string) error {
+
+	if name == "" && raw == levelResetKeyword {
+		return RestoreLevels()
+	}
+
+	if raw == levelResetKeyword {
+		ResetLevel(name)
+		return nil
+	}
+
+	level, err := logrus.ParseLevel(raw)
+	if err != nil {
+		return err
+	}
+
+	Infof("Changing log level for %v to %v", displayName(name), level)
+	SetLevel(name, level)
+
+	return nil
+
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return RootLoggerName
+	}
+	return name
+}
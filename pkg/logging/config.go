@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// enumerates the supported log formats
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+// enumerates the supported log outputs
+const (
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+)
+
+// EnvLogLevel is the environment variable that overrides the configured log level.
+const EnvLogLevel = "PG_LOG_LEVEL"
+
+// Configuration models the logging section of the application configuration.
+type Configuration struct {
+	Profile         string                 `yaml:"profile"`
+	Level           string                 `yaml:"level"`
+	Levels          map[string]string      `yaml:"levels"`
+	Format          string                 `yaml:"format"`
+	Output          string                 `yaml:"output"`
+	File            FileConfiguration      `yaml:"file"`
+	ServiceName     string                 `yaml:"service"`
+	IncludeHostname bool                   `yaml:"hostname"`
+	Fields          map[string]string      `yaml:"fields"`
+	Redaction       RedactionConfiguration `yaml:"redaction"`
+}
+
+// FileConfiguration describes the log file and its rotation policy.
+type FileConfiguration struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"maxSize"`
+	MaxAgeDays int    `yaml:"maxAge"`
+	MaxBackups int    `yaml:"maxBackups"`
+	Compress   bool   `yaml:"compress"`
+}
+
+// outputCloser holds the currently open log file, if any, so it can be closed
+// when logging is reconfigured.
+var outputCloser io.Closer
+
+// configuredLevel and configuredLevels hold the levels from the last
+// configuration so they can be restored after runtime changes.
+var (
+	configuredLevel  = logrus.InfoLevel
+	configuredLevels map[string]string
+)
+
+// Configure replaces the package level logger with one built from the given
+// configuration. The PG_LOG_LEVEL environment variable still takes precedence
+// over the configured level.
+func Configure(cfg Configuration) error {
+
+	formatter, err := resolveFormatter(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	output, closer, err := resolveOutput(cfg)
+	if err != nil {
+		return err
+	}
+
+	level, err := resolveLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	fields, err := resolveFields(cfg)
+	if err != nil {
+		return err
+	}
+
+	newLogger := logrus.New()
+	newLogger.SetFormatter(formatter)
+	newLogger.SetOutput(output)
+	newLogger.SetLevel(level)
+
+	if baseLogger != nil {
+		seen := make(map[logrus.Hook]bool)
+		for _, hooks := range baseLogger.Hooks {
+			for _, hook := range hooks {
+				if _, ok := hook.(*Redactor); ok || seen[hook] {
+					continue
+				}
+				seen[hook] = true
+				newLogger.AddHook(hook)
+			}
+		}
+	}
+
+	activeRedactor = nil
+	if !cfg.Redaction.Disabled {
+		activeRedactor = NewRedactor(cfg.Redaction)
+		newLogger.AddHook(activeRedactor)
+	}
+
+	if outputCloser != nil {
+		outputCloser.Close()
+	}
+	outputCloser = closer
+
+	SetProfile(cfg.Profile)
+
+	baseLogger = newLogger
+	fieldLogger = baseLogger.WithFields(fields)
+
+	configuredLevel = level
+	configuredLevels = cfg.Levels
+
+	return applyLevels(cfg.Levels)
+
+}
+
+func resolveFormatter(format string) (logrus.Formatter, error) {
+
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return &logrus.TextFormatter{}, nil
+	case FormatJSON:
+		return &logrus.JSONFormatter{}, nil
+	case FormatLogfmt:
+		return &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}, nil
+	}
+
+	return nil, errors.New("unsupported log format: " + format)
+
+}
+
+func resolveOutput(cfg Configuration) (io.Writer, io.Closer, error) {
+
+	switch strings.ToLower(cfg.Output) {
+	case "", OutputStderr:
+		return os.Stderr, nil, nil
+	case OutputFile:
+		if cfg.File.Path == "" {
+			return nil, nil, errors.New("no path configured for file log output")
+		}
+		writer := &lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSizeMB,
+			MaxAge:     cfg.File.MaxAgeDays,
+			MaxBackups: cfg.File.MaxBackups,
+			Compress:   cfg.File.Compress,
+		}
+		return writer, writer, nil
+	}
+
+	return nil, nil, errors.New("unsupported log output: " + cfg.Output)
+
+}
+
+func resolveLevel(configured string) (logrus.Level, error) {
+
+	level := os.Getenv(EnvLogLevel)
+	if level == "" {
+		level = configured
+	}
+
+	if level == "" {
+		return logrus.InfoLevel, nil
+	}
+
+	return logrus.ParseLevel(level)
+
+}
+
+func resolveFields(cfg Configuration) (logrus.Fields, error) {
+
+	fields := logrus.Fields{}
+
+	for key, value := range cfg.Fields {
+		fields[key] = value
+	}
+
+	if cfg.ServiceName != "" {
+		fields["service"] = cfg.ServiceName
+	}
+
+	if cfg.IncludeHostname {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		fields["hostname"] = hostname
+	}
+
+	return fields, nil
+
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureFormats(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(Configure(Configuration{}))
+	assert.IsType(&logrus.TextFormatter{}, baseLogger.Formatter)
+
+	assert.NoError(Configure(Configuration{Format: "JSON"}))
+	assert.IsType(&logrus.JSONFormatter{}, baseLogger.Formatter)
+
+	assert.NoError(Configure(Configuration{Format: FormatLogfmt}))
+	formatter, ok := baseLogger.Formatter.(*logrus.TextFormatter)
+	assert.True(ok)
+	assert.True(formatter.DisableColors)
+	assert.True(formatter.FullTimestamp)
+
+	assert.Error(Configure(Configuration{Format: "xml"}))
+
+}
+
+func TestConfigureOutput(t *testing.T) {
+
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "logging")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "service.log")
+
+	assert.NoError(Configure(Configuration{Output: OutputFile, File: FileConfiguration{Path: path}, Format: FormatJSON, ServiceName: "box-office"}))
+	Infoln("written to file")
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.True(strings.Contains(string(contents), `"msg":"written to file"`))
+	assert.True(strings.Contains(string(contents), `"service":"box-office"`))
+
+	assert.Error(Configure(Configuration{Output: OutputFile}))
+	assert.Error(Configure(Configuration{Output: "syslog"}))
+
+	assert.NoError(Configure(Configuration{}))
+	assert.Equal(os.Stderr, baseLogger.Out)
+
+}
+
+func TestConfigureLevel(t *testing.T) {
+
+	assert := assert.New(t)
+
+	defer os.Setenv(EnvLogLevel, os.Getenv(EnvLogLevel))
+	os.Unsetenv(EnvLogLevel)
+
+	assert.NoError(Configure(Configuration{}))
+	assert.Equal(logrus.InfoLevel, baseLogger.GetLevel())
+
+	assert.NoError(Configure(Configuration{Level: "warn"}))
+	assert.Equal(logrus.WarnLevel, baseLogger.GetLevel())
+
+	assert.Error(Configure(Configuration{Level: "loud"}))
+
+	//the environment takes precedence over the configuration
+	os.Setenv(EnvLogLevel, "trace")
+	assert.NoError(Configure(Configuration{Level: "warn"}))
+	assert.Equal(logrus.TraceLevel, baseLogger.GetLevel())
+
+	os.Setenv(EnvLogLevel, "loud")
+	assert.Error(Configure(Configuration{Level: "warn"}))
+
+	os.Unsetenv(EnvLogLevel)
+	assert.NoError(Configure(Configuration{Level: "info"}))
+
+}
@@ -1,38 +1,34 @@
 package logging
 
 import (
-	"encoding/json"
+	"os"
 	"strings"
 )
 
 const devLogFormat = "[DEV LOG] "
 
-// Dev is a log level that will only show up when running code compiled for
-// development. When code is compiled for a release, dev will be a dummy
-// function which does nothing.
-func Dev(values ...interface{}) {
-	arr := make([]interface{}, 0)
-	arr = append(arr, devLogFormat)
-	arr = append(arr, values...)
-	logger().Warn(arr...)
-}
+// ProfileProduction is the profile name that disables request and response
+// dumps, even in development builds.
+const ProfileProduction = "production"
+
+// EnvProfile is the environment variable that selects the active profile.
+const EnvProfile = "PG_PROFILE"
+
+var activeProfile string
 
-// Devf is similar to dev, except that it accepts a format string.
-func Devf(format string, values ...interface{}) {
-	var b strings.Builder
-	b.WriteString(devLogFormat)
-	b.WriteString(format)
-	logger().Warnf(b.String(), values...)
+// SetProfile sets the active profile. An empty profile falls back to the
+// PG_PROFILE environment variable.
+func SetProfile(profile string) {
+	activeProfile = profile
 }
 
-// DevJSON logs a struct as JSON. Similar to Dev, it will be compiled out in
-// releases.
-func DevJSON(value interface{}) {
-	arr := make([]interface{}, 0)
-	arr = append(arr, devLogFormat)
-	content, _ := json.Marshal(value)
-	arr = append(arr, string(content))
-	logger().Warn(arr...)
+// IsProduction returns true if the production profile is active.
+func IsProduction() bool {
+	profile := activeProfile
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	return strings.EqualFold(profile, ProfileProduction)
 }
 
 // TempLog is kept for backwards compatibility. It passes through to Dev.
@@ -0,0 +1,74 @@
+//go:build !release
+// +build !release
+
+package logging
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// DevEnabled is true when dev logging is compiled in. Build with the release
+// tag to compile it out.
+const DevEnabled = true
+
+// Dev is a log level that will only show up when running code compiled for
+// development. When code is compiled with the release tag, Dev is a dummy
+// function which does nothing.
+func Dev(values ...interface{}) {
+	arr := make([]interface{}, 0)
+	arr = append(arr, devLogFormat)
+	arr = append(arr, values...)
+	logger().Warn(arr...)
+}
+
+// Devf is similar to dev, except that it accepts a format string.
+func Devf(format string, values ...interface{}) {
+	var b strings.Builder
+	b.WriteString(devLogFormat)
+	b.WriteString(format)
+	logger().Warnf(b.String(), values...)
+}
+
+// DevJSON logs a struct as JSON. Similar to Dev, it will be compiled out in
+// releases.
+func DevJSON(value interface{}) {
+	arr := make([]interface{}, 0)
+	arr = append(arr, devLogFormat)
+	content := marshalRedacted(value, false)
+	arr = append(arr, string(content))
+	logger().Warn(arr...)
+}
+
+/*
+LogRequest logs a raw http request. Dumps are refused while the production
+profile is active.
+*/
+func LogRequest(r *http.Request) {
+
+	if IsProduction() {
+		Warnf("Refusing to dump request for %v %v in production profile", r.Method, r.URL.Path)
+		return
+	}
+
+	content, _ := httputil.DumpRequest(r, true)
+	Dev(string(content))
+
+}
+
+/*
+LogResponse logs a raw http response. Dumps are refused while the production
+profile is active.
+*/
+func LogResponse(r *http.Response) {
+
+	if IsProduction() {
+		Warnf("Refusing to dump response with status %v in production profile", r.StatusCode)
+		return
+	}
+
+	content, _ := httputil.DumpResponse(r, true)
+	Dev(string(content))
+
+}
@@ -0,0 +1,25 @@
+//go:build release
+// +build release
+
+package logging
+
+import "net/http"
+
+// DevEnabled is false when dev logging has been compiled out by the release
+// tag.
+const DevEnabled = false
+
+// Dev does nothing in release builds.
+func Dev(values ...interface{}) {}
+
+// Devf does nothing in release builds.
+func Devf(format string, values ...interface{}) {}
+
+// DevJSON does nothing in release builds.
+func DevJSON(value interface{}) {}
+
+// LogRequest does nothing in release builds.
+func LogRequest(r *http.Request) {}
+
+// LogResponse does nothing in release builds.
+func LogResponse(r *http.Response) {}
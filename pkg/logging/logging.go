@@ -1,10 +1,7 @@
 package logging
 
 import (
-	"encoding/json"
 	"encoding/xml"
-	"net/http"
-	"net/http/httputil"
 	"os"
 	"sync"
 
@@ -22,7 +19,11 @@ func Init(fields logrus.Fields) {
 
 	baseLogger.SetFormatter(&logrus.TextFormatter{})
 
-	logLevel := os.Getenv("PG_LOG_LEVEL")
+	if activeRedactor != nil {
+		baseLogger.AddHook(activeRedactor)
+	}
+
+	logLevel := os.Getenv(EnvLogLevel)
 	if logLevel != "" {
 		parsedLevel, err := logrus.ParseLevel(logLevel)
 		if err == nil {
@@ -34,7 +35,11 @@ func Init(fields logrus.Fields) {
 		baseLogger.SetLevel(logrus.InfoLevel)
 	}
 
+	configuredLevel = baseLogger.GetLevel()
+
 	fieldLogger = baseLogger.WithFields(fields)
+
+	rebindAll(fieldLogger)
 }
 
 // Handle cases where the package level logger was never initialized.
@@ -52,7 +57,12 @@ func logger() *logrus.Entry {
 LogJSONWithName logs json with caption.
 */
 func LogJSONWithName(name string, ptr interface{}) {
-	content, _ := json.MarshalIndent(ptr, "", " ")
+
+	if !DevEnabled {
+		return
+	}
+
+	content := marshalRedacted(ptr, true)
 
 	Devf("%s: %s", name, content)
 }
@@ -61,48 +71,38 @@ func LogJSONWithName(name string, ptr interface{}) {
 LogJSON logs json to the console.
 */
 func LogJSON(ptr interface{}) {
-	content, _ := json.MarshalIndent(ptr, "", " ")
+
+	if !DevEnabled {
+		return
+	}
+
+	content := marshalRedacted(ptr, true)
 
 	Dev(string(content))
 }
 
 // LogXMLWithName logs XML with a caption.
 func LogXMLWithName(name string, ptr interface{}) {
-	content, _ := xml.MarshalIndent(ptr, "", " ")
-
-	Devf("%s: %s", name, content)
-}
 
-/*
-LogRequest logs a raw http request.
-*/
-func LogRequest(r *http.Request) {
-
-	content, _ := httputil.DumpRequest(r, true)
-	Dev(string(content))
-
-}
-
-/*
-LogResponse logs a raw http request.
-*/
-func LogResponse(r *http.Response) {
+	if !DevEnabled {
+		return
+	}
 
-	content, _ := httputil.DumpResponse(r, true)
-	Dev(string(content))
+	content, _ := xml.MarshalIndent(ptr, "", " ")
 
+	Devf("%s: %s", name, content)
 }
 
 // Everything after this is just boilerplate to invoke logrus
 
 // SetFormatter wraps the logrus SetFormatter function
 func SetFormatter(formatter logrus.Formatter) {
-	baseLogger.SetFormatter(formatter)
+	logger().Logger.SetFormatter(formatter)
 }
 
 // AddHook wraps the logrus AddHook function
 func AddHook(hook logrus.Hook) {
-	baseLogger.AddHook(hook)
+	logger().Logger.AddHook(hook)
 }
 
 // WithFields wraps the logrus WithFields function.
@@ -0,0 +1,308 @@
+package logging
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RootLoggerName addresses the package level logger when setting levels.
+const RootLoggerName = "root"
+
+var (
+	namedLock    sync.RWMutex
+	namedLoggers = make(map[string]*Logger)
+)
+
+// Logger is a named logger, usually one per module or package. Named loggers
+// share the output, formatter and hooks of the package level logger, but can
+// have their own level. Without an explicit level they follow the package
+// level logger.
+type Logger struct {
+	name     string
+	mu       sync.RWMutex
+	level    logrus.Level
+	explicit bool
+	entry    *logrus.Entry
+}
+
+// For returns the named logger for the given module or package, creating it if
+// needed.
+func For(name string) *Logger {
+
+	name = strings.ToLower(name)
+
+	// resolve the package level logger first since initializing it rebinds
+	// every named logger under the registry lock
+	root := logger()
+
+	namedLock.RLock()
+	named, ok := namedLoggers[name]
+	namedLock.RUnlock()
+
+	if ok {
+		return named
+	}
+
+	namedLock.Lock()
+	defer namedLock.Unlock()
+
+	if named, ok = namedLoggers[name]; ok {
+		return named
+	}
+
+	named = &Logger{name: name}
+	named.rebind(root)
+	namedLoggers[name] = named
+
+	return named
+
+}
+
+// SetLevel sets the level for the named logger. The root name sets the level
+// of the package level logger.
+func SetLevel(name string, level logrus.Level) {
+
+	name = strings.ToLower(name)
+
+	if name == RootLoggerName || name == "" {
+		root := logger()
+		root.Logger.SetLevel(level)
+		rebindAll(root)
+		return
+	}
+
+	named := For(name)
+	named.mu.Lock()
+	named.level = level
+	named.explicit = true
+	named.mu.Unlock()
+	named.rebind(logger())
+
+}
+
+// ResetLevel clears an explicit level so that the named logger follows the
+// package level logger again.
+func ResetLevel(name string) {
+
+	named := For(name)
+	named.mu.Lock()
+	named.explicit = false
+	named.mu.Unlock()
+	named.rebind(logger())
+
+}
+
+// Levels returns the effective level of the package level logger and every
+// named logger.
+func Levels() map[string]string {
+
+	results := map[string]string{
+		RootLoggerName: logger().Logger.GetLevel().String(),
+	}
+
+	namedLock.RLock()
+	defer namedLock.RUnlock()
+
+	for name, named := range namedLoggers {
+		results[name] = named.Level().String()
+	}
+
+	return results
+
+}
+
+// LoggerNames returns the names of all named loggers in sorted order.
+func LoggerNames() []string {
+
+	namedLock.RLock()
+	defer namedLock.RUnlock()
+
+	names := make([]string, 0, len(namedLoggers))
+	for name := range namedLoggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+
+}
+
+// applyLevels clears any explicit named logger levels and sets the given
+// ones.
+func applyLevels(levels map[string]string) error {
+
+	namedLock.RLock()
+	for _, named := range namedLoggers {
+		named.mu.Lock()
+		named.explicit = false
+		named.mu.Unlock()
+	}
+	namedLock.RUnlock()
+
+	for name, raw := range levels {
+		level, err := logrus.ParseLevel(raw)
+		if err != nil {
+			return err
+		}
+		SetLevel(name, level)
+	}
+
+	rebindAll(logger())
+
+	return nil
+
+}
+
+// RestoreLevels discards runtime level changes and restores the levels from
+// the last configuration.
+func RestoreLevels() error {
+
+	root := logger()
+	root.Logger.SetLevel(configuredLevel)
+
+	return applyLevels(configuredLevels)
+
+}
+
+// rebindAll points every named logger at the current package level logger.
+// It's called whenever the package level logger is replaced.
+func rebindAll(root *logrus.Entry) {
+
+	namedLock.RLock()
+	defer namedLock.RUnlock()
+
+	for _, named := range namedLoggers {
+		named.rebind(root)
+	}
+
+}
+
+// rebind builds a logrus logger that shares everything with the parent
+// except the level.
+func (named *Logger) rebind(parent *logrus.Entry) {
+
+	named.mu.Lock()
+	defer named.mu.Unlock()
+
+	level := parent.Logger.GetLevel()
+	if named.explicit {
+		level = named.level
+	}
+
+	child := &logrus.Logger{
+		Out:          parent.Logger.Out,
+		Hooks:        parent.Logger.Hooks,
+		Formatter:    parent.Logger.Formatter,
+		ReportCaller: parent.Logger.ReportCaller,
+		Level:        level,
+		ExitFunc:     os.Exit,
+	}
+
+	named.entry = child.WithFields(parent.Data).WithField("module", named.name)
+
+}
+
+func (named *Logger) current() *logrus.Entry {
+	named.mu.RLock()
+	defer named.mu.RUnlock()
+	return named.entry
+}
+
+// Name returns the name of the logger.
+func (named *Logger) Name() string {
+	return named.name
+}
+
+// Level returns the effective level of the logger.
+func (named *Logger) Level() logrus.Level {
+	return named.current().Logger.GetLevel()
+}
+
+// IsLevelEnabled returns true if messages at the given level will be logged.
+func (named *Logger) IsLevelEnabled(level logrus.Level) bool {
+	return named.current().Logger.IsLevelEnabled(level)
+}
+
+// WithFields wraps the logrus WithFields function.
+func (named *Logger) WithFields(fields logrus.Fields) *logrus.Entry {
+	return named.current().WithFields(fields)
+}
+
+// Tracef wraps the logrus Tracef function
+func (named *Logger) Tracef(format string, args ...interface{}) {
+	named.current().Tracef(format, args...)
+}
+
+// Debugf wraps the logrus Debugf function
+func (named *Logger) Debugf(format string, args ...interface{}) {
+	named.current().Debugf(format, args...)
+}
+
+// Infof wraps the logrus Infof function
+func (named *Logger) Infof(format string, args ...interface{}) {
+	named.current().Infof(format, args...)
+}
+
+// Warnf wraps the logrus Warnf function
+func (named *Logger) Warnf(format string, args ...interface{}) {
+	named.current().Warnf(format, args...)
+}
+
+// Errorf wraps the logrus Errorf function
+func (named *Logger) Errorf(format string, args ...interface{}) {
+	named.current().Errorf(format, args...)
+}
+
+// Trace wraps the logrus Trace function
+func (named *Logger) Trace(args ...interface{}) {
+	named.current().Trace(args...)
+}
+
+// Debug wraps the logrus Debug function
+func (named *Logger) Debug(args ...interface{}) {
+	named.current().Debug(args...)
+}
+
+// Info wraps the logrus Info function
+func (named *Logger) Info(args ...interface{}) {
+	named.current().Info(args...)
+}
+
+// Warn wraps the logrus Warn function
+func (named *Logger) Warn(args ...interface{}) {
+	named.current().Warn(args...)
+}
+
+// Error wraps the logrus Error function
+func (named *Logger) Error(args ...interface{}) {
+	named.current().Error(args...)
+}
+
+// Traceln wraps the logrus Traceln function
+func (named *Logger) Traceln(args ...interface{}) {
+	named.current().Traceln(args...)
+}
+
+// Debugln wraps the logrus Debugln function
+func (named *Logger) Debugln(args ...interface{}) {
+	named.current().Debugln(args...)
+}
+
+// Infoln wraps the logrus Infoln function
+func (named *Logger) Infoln(args ...interface{}) {
+	named.current().Infoln(args...)
+}
+
+// Warnln wraps the logrus Warnln function
+func (named *Logger) Warnln(args ...interface{}) {
+	named.current().Warnln(args...)
+}
+
+// Errorln wraps the logrus Errorln function
+func (named *Logger) Errorln(args ...interface{}) {
+	named.current().Errorln(args...)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedLoggerLevels(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(Configure(Configuration{Level: "info"}))
+
+	named := For("NamedTest")
+
+	assert.Same(named, For("namedtest"))
+	assert.Equal(logrus.InfoLevel, named.Level())
+
+	SetLevel("namedtest", logrus.TraceLevel)
+	assert.True(named.IsLevelEnabled(logrus.TraceLevel))
+	assert.False(logger().Logger.IsLevelEnabled(logrus.TraceLevel))
+
+	SetLevel(RootLoggerName, logrus.WarnLevel)
+	assert.Equal(logrus.TraceLevel, named.Level())
+
+	ResetLevel("namedtest")
+	assert.Equal(logrus.WarnLevel, named.Level())
+
+	assert.NoError(RestoreLevels())
+	assert.Equal(logrus.InfoLevel, named.Level())
+
+	assert.NoError(Configure(Configuration{Levels: map[string]string{"namedtest": "debug"}}))
+	assert.Equal(logrus.DebugLevel, named.Level())
+
+}
+
+func TestLevelHandler(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(Configure(Configuration{Level: "info"}))
+
+	handler := LevelHandler()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-levels?logger=handlertest&level=trace", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.True(strings.Contains(rec.Body.String(), `"handlertest":"trace"`))
+	assert.Equal(logrus.TraceLevel, For("handlertest").Level())
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/log-levels?logger=handlertest&level=loud", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(http.StatusBadRequest, rec.Code)
+
+}
@@ -0,0 +1,274 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces sensitive values in log output.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedFields lists the field names masked when no others are
+// configured.
+var DefaultRedactedFields = []string{"password", "password_hash", "inner_salt", "token"}
+
+var (
+	panCandidate = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// activeRedactor is the redactor attached to the package level logger, or nil
+// if redaction has been disabled.
+var activeRedactor = NewRedactor(RedactionConfiguration{})
+
+// RedactionConfiguration models the redaction settings for log output.
+// Redaction is on unless explicitly disabled.
+type RedactionConfiguration struct {
+	Disabled     bool     `yaml:"disabled"`
+	Fields       []string `yaml:"fields"`
+	Allow        []string `yaml:"allow"`
+	IgnorePANs   bool     `yaml:"ignorePans"`
+	IgnoreEmails bool     `yaml:"ignoreEmails"`
+}
+
+// Redactor masks sensitive field values, card numbers and email addresses.
+type Redactor struct {
+	fields       map[string]bool
+	allow        map[string]bool
+	maskPANs     bool
+	maskEmails   bool
+	keyValuePair *regexp.Regexp
+}
+
+// NewRedactor builds a redactor from the given configuration. The default
+// field names are always masked unless allowlisted.
+func NewRedactor(cfg RedactionConfiguration) *Redactor {
+
+	redactor := &Redactor{
+		fields:     make(map[string]bool),
+		allow:      make(map[string]bool),
+		maskPANs:   !cfg.IgnorePANs,
+		maskEmails: !cfg.IgnoreEmails,
+	}
+
+	for _, field := range DefaultRedactedFields {
+		redactor.fields[strings.ToLower(field)] = true
+	}
+
+	for _, field := range cfg.Fields {
+		redactor.fields[strings.ToLower(field)] = true
+	}
+
+	for _, field := range cfg.Allow {
+		redactor.allow[strings.ToLower(field)] = true
+	}
+
+	names := make([]string, 0, len(redactor.fields))
+	for field := range redactor.fields {
+		if !redactor.allow[field] {
+			names = append(names, regexp.QuoteMeta(field))
+		}
+	}
+
+	if len(names) > 0 {
+		redactor.keyValuePair = regexp.MustCompile(`(?i)("?\b(?:` + strings.Join(names, "|") + `)\b"?\s*[:=]\s*"?)([^"&,\s}]*)`)
+	}
+
+	return redactor
+
+}
+
+// IsSensitive returns true if values for the given field name are masked.
+func (redactor *Redactor) IsSensitive(field string) bool {
+	field = strings.ToLower(field)
+	return redactor.fields[field] && !redactor.allow[field]
+}
+
+// IsAllowed returns true if the given field name is exempt from redaction.
+func (redactor *Redactor) IsAllowed(field string) bool {
+	return redactor.allow[strings.ToLower(field)]
+}
+
+// RedactString masks sensitive key/value pairs, card numbers and email
+// addresses embedded in free form text.
+func (redactor *Redactor) RedactString(value string) string {
+
+	if redactor.keyValuePair != nil {
+		value = redactor.keyValuePair.ReplaceAllString(value, "${1}"+RedactedValue)
+	}
+
+	if redactor.maskPANs {
+		value = panCandidate.ReplaceAllStringFunc(value, maskPAN)
+	}
+
+	if redactor.maskEmails {
+		value = emailPattern.ReplaceAllStringFunc(value, maskEmail)
+	}
+
+	return value
+
+}
+
+// RedactJSON masks sensitive values in a JSON document. Content that isn't
+// valid JSON is redacted as free form text.
+func (redactor *Redactor) RedactJSON(content []byte) []byte {
+
+	var doc interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return []byte(redactor.RedactString(string(content)))
+	}
+
+	redacted, err := json.Marshal(redactor.redactValue("", doc))
+	if err != nil {
+		return []byte(redactor.RedactString(string(content)))
+	}
+
+	return redacted
+
+}
+
+func (redactor *Redactor) redactValue(field string, value interface{}) interface{} {
+
+	if field != "" {
+		if redactor.IsAllowed(field) {
+			return value
+		}
+		if redactor.IsSensitive(field) {
+			return RedactedValue
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, val := range typed {
+			result[key] = redactor.redactValue(key, val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for idx, val := range typed {
+			result[idx] = redactor.redactValue("", val)
+		}
+		return result
+	case string:
+		return redactor.RedactString(typed)
+	}
+
+	return value
+
+}
+
+// Levels implements the logrus.Hook interface.
+func (redactor *Redactor) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus.Hook interface. The entry's field map is shared
+// with its parent entry, so redacted fields are written to a copy.
+func (redactor *Redactor) Fire(entry *logrus.Entry) error {
+
+	entry.Message = redactor.RedactString(entry.Message)
+
+	data := make(logrus.Fields, len(entry.Data))
+	for key, val := range entry.Data {
+		switch {
+		case redactor.IsAllowed(key):
+			data[key] = val
+		case redactor.IsSensitive(key):
+			data[key] = RedactedValue
+		default:
+			if str, ok := val.(string); ok {
+				data[key] = redactor.RedactString(str)
+			} else {
+				data[key] = val
+			}
+		}
+	}
+	entry.Data = data
+
+	return nil
+
+}
+
+// marshalRedacted converts a value to JSON and runs it through the active
+// redactor, if any.
+func marshalRedacted(value interface{}, indent bool) []byte {
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	if activeRedactor != nil {
+		content = activeRedactor.RedactJSON(content)
+	}
+
+	if !indent {
+		return content
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, content, "", " "); err != nil {
+		return content
+	}
+
+	return b.Bytes()
+
+}
+
+// maskPAN masks digit sequences that pass the Luhn check, leaving the last
+// four digits visible.
+func maskPAN(candidate string) string {
+
+	digits := make([]byte, 0, len(candidate))
+	for i := 0; i < len(candidate); i++ {
+		if candidate[i] >= '0' && candidate[i] <= '9' {
+			digits = append(digits, candidate[i])
+		}
+	}
+
+	if !luhnValid(digits) {
+		return candidate
+	}
+
+	return strings.Repeat("*", len(digits)-4) + string(digits[len(digits)-4:])
+
+}
+
+func luhnValid(digits []byte) bool {
+
+	sum := 0
+	double := false
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+
+}
+
+// maskEmail hides the local part of an email address.
+func maskEmail(email string) string {
+
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return RedactedValue
+	}
+
+	return email[:1] + "***" + email[at:]
+
+}
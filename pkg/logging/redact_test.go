@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactString(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "ValidPAN",
+			input:  "charging card 4111 1111 1111 1111 now",
+			expect: "charging card ************1111 now",
+		},
+		{
+			name:   "InvalidLuhn",
+			input:  "order 4111111111111112",
+			expect: "order 4111111111111112",
+		},
+		{
+			name:   "Email",
+			input:  "reset sent to jane.doe@example.com",
+			expect: "reset sent to j***@example.com",
+		},
+		{
+			name:   "FormField",
+			input:  "email=x&password=hunter2&remember=true",
+			expect: "email=x&password=[REDACTED]&remember=true",
+		},
+		{
+			name:   "JSONField",
+			input:  `{"token": "abc123", "id": 5}`,
+			expect: `{"token": "[REDACTED]", "id": 5}`,
+		},
+	}
+
+	redactor := NewRedactor(RedactionConfiguration{})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, redactor.RedactString(test.input))
+		})
+	}
+
+}
+
+func TestRedactJSON(t *testing.T) {
+
+	assert := assert.New(t)
+
+	redactor := NewRedactor(RedactionConfiguration{
+		Fields: []string{"pin"},
+		Allow:  []string{"contact"},
+	})
+
+	result := redactor.RedactJSON([]byte(`{"user":{"password_hash":"x","pin":"1234","email":"a@b.com"},"contact":"c@d.com"}`))
+
+	assert.Equal(`{"contact":"c@d.com","user":{"email":"a***@b.com","password_hash":"[REDACTED]","pin":"[REDACTED]"}}`, string(result))
+
+}
+
+func TestRedactionHook(t *testing.T) {
+
+	assert := assert.New(t)
+
+	redactor := NewRedactor(RedactionConfiguration{})
+
+	parent := logrus.NewEntry(logrus.New()).WithFields(logrus.Fields{"inner_salt": "salty", "user": "bob"})
+	entry := *parent
+	entry.Message = "login for bob@example.com"
+
+	assert.NoError(redactor.Fire(&entry))
+
+	assert.Equal("login for b***@example.com", entry.Message)
+	assert.Equal(RedactedValue, entry.Data["inner_salt"])
+	assert.Equal("bob", entry.Data["user"])
+	assert.Equal("salty", parent.Data["inner_salt"], "parent fields must not be modified")
+
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+// +build !windows
+
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WatchLevelSignals lets operators change log levels with signals. SIGUSR1
+// makes the package level logger one level more verbose, wrapping from trace
+// back to the configured level. SIGUSR2 restores the configured levels.
+func WatchLevelSignals() {
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for sig := range signals {
+			switch sig {
+			case syscall.SIGUSR1:
+				level := logger().Logger.GetLevel() + 1
+				if level > logrus.TraceLevel {
+					level = configuredLevel
+				}
+				SetLevel(RootLoggerName, level)
+				Warnf("Log level changed to %v by signal", level)
+			case syscall.SIGUSR2:
+				if err := RestoreLevels(); err != nil {
+					Errorf("Failed to restore log levels: %v", err)
+				}
+				Warnln("Log levels restored by signal")
+			}
+		}
+	}()
+
+}
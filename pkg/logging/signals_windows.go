@@ -0,0 +1,4 @@
+package logging
+
+// WatchLevelSignals does nothing on Windows, which has no user signals.
+func WatchLevelSignals() {}
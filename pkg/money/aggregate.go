@@ -0,0 +1,115 @@
+package money
+
+import (
+	"fmt"
+)
+
+// checkValue rejects nil amounts in an aggregation.
+func checkValue(i int, value *Currency) error {
+
+	if value == nil {
+		return fmt.Errorf("%w: value %v is nil", ErrInvalidAmount, i)
+	}
+
+	return nil
+
+}
+
+/*
+Sum adds up the amounts, returning an error rather than panicking if they're
+in different currencies. The sum of no amounts is zero.
+*/
+func Sum(values ...*Currency) (*Currency, error) {
+
+	total := Zero()
+
+	for i, value := range values {
+		if err := checkValue(i, value); err != nil {
+			return nil, err
+		}
+		//add a copy, since adding normalizes the operand in place
+		operand := *value
+		var err error
+		if total, err = total.AddChecked(&operand); err != nil {
+			return nil, err
+		}
+	}
+
+	return total, nil
+
+}
+
+/*
+Min returns a copy of the smallest amount, or an error if there are none or
+they're in different currencies.
+*/
+func Min(values ...*Currency) (*Currency, error) {
+	return extreme(values, -1)
+}
+
+/*
+Max returns a copy of the largest amount, or an error if there are none or
+they're in different currencies.
+*/
+func Max(values ...*Currency) (*Currency, error) {
+	return extreme(values, 1)
+}
+
+// extreme returns the first amount that compares to every other as the given
+// sign does.
+func extreme(values []*Currency, sign int) (*Currency, error) {
+
+	if len(values) == 0 {
+		return nil, ErrNoValues
+	}
+
+	var result *Currency
+
+	for i, value := range values {
+		if err := checkValue(i, value); err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = value
+			continue
+		}
+		//compare copies, since comparing normalizes both operands in place
+		a, b := *value, *result
+		cmp, err := a.CompareChecked(&b)
+		if err != nil {
+			return nil, err
+		}
+		if cmp == sign {
+			result = value
+		}
+	}
+
+	copied := *result
+
+	return &copied, nil
+
+}
+
+/*
+Average returns the mean of the amounts, rounded to their precision with the
+given mode, or an error if there are none or they're in different
+currencies.
+*/
+func Average(values []*Currency, mode RoundingMode) (*Currency, error) {
+
+	if len(values) == 0 {
+		return nil, ErrNoValues
+	}
+
+	total, err := Sum(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	if total.IsZero() {
+		return total, nil
+	}
+
+	return total.DivideIntChecked(len(values), mode)
+
+}
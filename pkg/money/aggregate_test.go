@@ -0,0 +1,77 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregates(t *testing.T) {
+
+	assert := assert.New(t)
+
+	values := []*Currency{
+		ParseCurrency(CurrencyCodeUSD, "10.00"),
+		ParseCurrency(CurrencyCodeUSD, "-2.50"),
+		Zero(),
+		ParseCurrency(CurrencyCodeUSD, "3.01"),
+	}
+
+	sum, err := Sum(values...)
+	assert.NoError(err)
+	assert.Equal("10.51", sum.FormatCurrency())
+	assert.Equal(CurrencyCodeUSD, sum.CurrencyCode)
+
+	sum, err = Sum()
+	assert.NoError(err)
+	assert.True(sum.IsZero())
+
+	min, err := Min(values...)
+	assert.NoError(err)
+	assert.Equal("-2.50", min.FormatCurrency())
+
+	max, err := Max(values...)
+	assert.NoError(err)
+	assert.Equal("10.00", max.FormatCurrency())
+
+	//results are copies
+	max.Integer = 99
+	assert.Equal(10, values[0].Integer)
+
+	//inputs aren't normalized in place
+	bare := []*Currency{{}, {Integer: 3, CurrencyCode: CurrencyCodeUSD}}
+	_, err = Min(bare...)
+	assert.NoError(err)
+	_, err = Max(bare...)
+	assert.NoError(err)
+	_, err = Sum(bare...)
+	assert.NoError(err)
+	assert.Equal(Currency{}, *bare[0])
+	assert.Equal(Currency{Integer: 3, CurrencyCode: CurrencyCodeUSD}, *bare[1])
+
+	average, err := Average(values, RoundNearest)
+	assert.NoError(err)
+	assert.Equal("2.63", average.FormatCurrency())
+
+	average, err = Average(values, RoundDown)
+	assert.NoError(err)
+	assert.Equal("2.62", average.FormatCurrency())
+
+	mixed := append(values, ParseCurrency(CurrencyCodeCAD, "1.00"))
+
+	_, err = Sum(mixed...)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+	_, err = Max(mixed...)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+	_, err = Average(mixed, RoundNearest)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = Min()
+	assert.Equal(ErrNoValues, err)
+	_, err = Average(nil, RoundNearest)
+	assert.Equal(ErrNoValues, err)
+	_, err = Sum(values[0], nil)
+	assert.True(errors.Is(err, ErrInvalidAmount))
+
+}
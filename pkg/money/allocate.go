@@ -0,0 +1,122 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// ErrInvalidAllocation is the panic value for allocations without any
+// positive ratio, or with negative ratios.
+var ErrInvalidAllocation = errors.New("allocation ratios must be non-negative and not all zero")
+
+/*
+Allocate divides the currency into parts proportional to the given ratios.
+Each part is rounded toward zero to the currency's smallest unit, and the
+leftover units go one at a time to the parts that lost the most to rounding,
+earlier parts first on ties. The parts always add up to the original amount.
+*/
+func (base *Currency) Allocate(ratios ...int) []*Currency {
+
+	weights := make([]*big.Int, len(ratios))
+	for i, ratio := range ratios {
+		weights[i] = big.NewInt(int64(ratio))
+	}
+
+	return base.allocate(weights)
+
+}
+
+/*
+Split divides the currency into n parts that differ by at most one of the
+currency's smallest unit and add up to the original amount.
+*/
+func (base *Currency) Split(n int) []*Currency {
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return base.Allocate(ratios...)
+
+}
+
+/*
+AllocateBPS is like Allocate, but takes basis points as ratios, which don't
+need to share a denominator. The basis points are relative to each other, so
+they don't need to add up to 100%. Ratios without a denominator count as
+zero, as they do in Rat.
+*/
+func (base *Currency) AllocateBPS(ratios []*BasisPoints) []*Currency {
+
+	exact := make([]*big.Rat, len(ratios))
+	for i, ratio := range ratios {
+		exact[i] = ratio.Rat()
+	}
+
+	//bring every ratio over a common denominator
+	common := big.NewInt(1)
+	for _, ratio := range exact {
+		gcd := new(big.Int).GCD(nil, nil, common, ratio.Denom())
+		common.Mul(common, new(big.Int).Quo(ratio.Denom(), gcd))
+	}
+
+	weights := make([]*big.Int, len(exact))
+	for i, ratio := range exact {
+		weights[i] = new(big.Int).Mul(ratio.Num(), new(big.Int).Quo(common, ratio.Denom()))
+	}
+
+	return base.allocate(weights)
+
+}
+
+func (base *Currency) allocate(weights []*big.Int) []*Currency {
+
+	total := new(big.Int)
+	for _, weight := range weights {
+		if weight.Sign() < 0 {
+			panic(ErrInvalidAllocation)
+		}
+		total.Add(total, weight)
+	}
+
+	if total.Sign() == 0 {
+		panic(ErrInvalidAllocation)
+	}
+
+	base.normalize()
+
+	amount := base.scaled()
+	shares := make([]*big.Int, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	leftover := new(big.Int).Set(amount)
+
+	for i, weight := range weights {
+		shares[i], remainders[i] = new(big.Int).QuoRem(new(big.Int).Mul(amount, weight), total, new(big.Int))
+		remainders[i].Abs(remainders[i])
+		leftover.Sub(leftover, shares[i])
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+
+	unit := big.NewInt(int64(leftover.Sign()))
+	for i := 0; leftover.Sign() != 0; i++ {
+		shares[order[i]].Add(shares[order[i]], unit)
+		leftover.Sub(leftover, unit)
+	}
+
+	results := make([]*Currency, len(shares))
+	for i, share := range shares {
+		results[i] = fromScaled(base.CurrencyCode, share, base.DecimalDenominator)
+	}
+
+	return results
+
+}
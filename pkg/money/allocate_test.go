@@ -0,0 +1,96 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertSumsTo(t *testing.T, expected *Currency, parts []*Currency) {
+
+	sum := Zero()
+	for _, part := range parts {
+		sum = sum.Add(part)
+	}
+
+	assert.Equal(t, expected.String(), sum.String())
+
+}
+
+func formatAll(parts []*Currency) []string {
+
+	result := make([]string, len(parts))
+	for i, part := range parts {
+		result[i] = part.String()
+	}
+
+	return result
+
+}
+
+func TestSplit(t *testing.T) {
+
+	assert := assert.New(t)
+
+	amount := New(CurrencyCodeUSD, "100.00")
+	parts := amount.Split(3)
+	assert.Equal([]string{"33.34", "33.33", "33.33"}, formatAll(parts))
+	assertSumsTo(t, amount, parts)
+
+	negative := New(CurrencyCodeUSD, "-0.05")
+	parts = negative.Split(3)
+	assert.Equal([]string{"-0.02", "-0.02", "-0.01"}, formatAll(parts))
+	assertSumsTo(t, negative, parts)
+
+	parts = New(CurrencyCodeUSD, "0.01").Split(4)
+	assert.Equal([]string{"0.01", "0.00", "0.00", "0.00"}, formatAll(parts))
+
+}
+
+func TestAllocate(t *testing.T) {
+
+	assert := assert.New(t)
+
+	amount := New(CurrencyCodeUSD, "0.05")
+	parts := amount.Allocate(3, 7)
+	assert.Equal([]string{"0.02", "0.03"}, formatAll(parts))
+	assertSumsTo(t, amount, parts)
+
+	amount = New(CurrencyCodeUSD, "100.00")
+	parts = amount.Allocate(1, 0, 2)
+	assert.Equal([]string{"33.33", "0.00", "66.67"}, formatAll(parts))
+	assertSumsTo(t, amount, parts)
+
+	btc := New(CurrencyCodeBTC, "1.00000001")
+	parts = btc.Allocate(1, 1)
+	assert.Equal([]string{"0.50000001", "0.50000000"}, formatAll(parts))
+
+	assert.PanicsWithValue(ErrInvalidAllocation, func() { amount.Allocate() })
+	assert.PanicsWithValue(ErrInvalidAllocation, func() { amount.Allocate(0, 0) })
+	assert.PanicsWithValue(ErrInvalidAllocation, func() { amount.Allocate(1, -1) })
+
+}
+
+func TestAllocateBPS(t *testing.T) {
+
+	assert := assert.New(t)
+
+	amount := New(CurrencyCodeUSD, "1000.00")
+
+	ratios := []*BasisPoints{NewBasisPoints(7000), NewFractionalBasisPoints(29995, 100000), NewFractionalBasisPoints(5, 100000)}
+	parts := amount.AllocateBPS(ratios)
+	assert.Equal([]string{"700.00", "299.95", "0.05"}, formatAll(parts))
+	assertSumsTo(t, amount, parts)
+
+	amount = New(CurrencyCodeUSD, "10.00")
+	parts = amount.AllocateBPS([]*BasisPoints{NewBasisPoints(1), NewBasisPoints(1), NewBasisPoints(1)})
+	assert.Equal([]string{"3.34", "3.33", "3.33"}, formatAll(parts))
+
+	//a ratio without a denominator counts as zero
+	parts = amount.AllocateBPS([]*BasisPoints{NewBasisPoints(1), {Numerator: 5}})
+	assert.Equal([]string{"10.00", "0.00"}, formatAll(parts))
+
+	assert.PanicsWithValue(ErrInvalidAllocation, func() { amount.AllocateBPS([]*BasisPoints{{Numerator: 5}}) })
+	assert.PanicsWithValue(ErrInvalidAllocation, func() { amount.AllocateBPS([]*BasisPoints{NewBasisPoints(1), {Numerator: 1, Denominator: -3}}) })
+
+}
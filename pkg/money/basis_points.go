@@ -2,10 +2,14 @@ package money
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // constants for use in great business success
@@ -25,6 +29,17 @@ func (bps *BasisPoints) Float64() float64 {
 	return float64(bps.Numerator) / float64(bps.Denominator)
 }
 
+// Rat returns the ratio as an exact fraction. A zero or unset ratio is zero.
+func (bps *BasisPoints) Rat() *big.Rat {
+
+	if bps == nil || bps.Denominator == 0 {
+		return new(big.Rat)
+	}
+
+	return big.NewRat(int64(bps.Numerator), int64(bps.Denominator))
+
+}
+
 // ValidateBasisPointsSequential validates that the given basis points are sequential
 func ValidateBasisPointsSequential(values ...*BasisPoints) error {
 
@@ -66,40 +81,66 @@ func NewFractionalBasisPoints(numerator int, denominator int) *BasisPoints {
 
 }
 
-// ParseBasisPointsDecimal parses a bps ratio formatted as a decimal coefficient
+/*
+ParseBasisPointsDecimal parses a bps ratio formatted as a decimal coefficient,
+e.g. 0.0275 or 1.25, as written by DecimalString. The denominator is at least
+10,000, or a larger power of ten if the decimal has more places.
+*/
 func ParseBasisPointsDecimal(decString string) (*BasisPoints, error) {
 
-	decString = strings.TrimRight(decString, "0")
+	value := strings.TrimSpace(decString)
+
+	negative := strings.HasPrefix(value, "-")
+	if negative {
+		value = value[1:]
+	}
+
+	whole, fraction := value, ""
+	if dotIndex := strings.Index(value, "."); dotIndex >= 0 {
+		whole, fraction = value[:dotIndex], value[dotIndex+1:]
+	}
 
-	tokens := strings.Split(decString, ".")
+	if value == "" || value == "." || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%w: %q is not a decimal ratio", ErrInvalidAmount, decString)
+	}
 
-	inputDecPlaces := len(tokens[1])
+	fraction = strings.TrimRight(fraction, "0")
+
+	places := len(fraction)
 	minDecPlaces := int(math.Log10(BpsPerWhole))
+	if places < minDecPlaces {
+		fraction += strings.Repeat("0", minDecPlaces-places)
+		places = minDecPlaces
+	}
 
-	if inputDecPlaces <= minDecPlaces {
-		//add padding right zeros
-		paddedString := tokens[1] + strings.Repeat("0", minDecPlaces-inputDecPlaces)
-		numer, err := strconv.Atoi(paddedString)
-		if err != nil {
-			return nil, err
-		}
-		result := BasisPoints{
-			Numerator:   numer,
-			Denominator: BpsPerWhole,
-		}
-		return &result, nil
+	numerator, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok || places > maxDecimalPlaces {
+		return nil, fmt.Errorf("%w: %q is not a decimal ratio", ErrInvalidAmount, decString)
+	}
+
+	if negative {
+		numerator.Neg(numerator)
 	}
 
-	denom := int(math.Pow10(inputDecPlaces))
-	numer, err := strconv.Atoi(tokens[1])
+	numer, err := toInt(numerator)
 	if err != nil {
 		return nil, err
 	}
-	result := BasisPoints{
-		Numerator:   numer,
-		Denominator: denom,
+
+	return &BasisPoints{Numerator: numer, Denominator: pow10(places)}, nil
+
+}
+
+// isDigits returns true if the string is empty or holds only decimal digits.
+func isDigits(value string) bool {
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	return &result, nil
+
+	return true
 
 }
 
@@ -219,55 +260,130 @@ func (bps BasisPoints) Value() (driver.Value, error) {
 
 /*
 Scan implements the scan interface in order to support sql serialization.
+NULL scans as zero.
 */
 func (bps *BasisPoints) Scan(src interface{}) error {
 
+	var decString string
+
 	switch dbVal := src.(type) {
+	case nil:
+		*bps = BasisPoints{}
+		return nil
 	case []uint8:
-		floatString := string(dbVal)
-		workingCopy, err := ParseBasisPointsDecimal(floatString)
-		if err != nil {
-			return err
-		}
-		bps.Numerator = workingCopy.Numerator
-		bps.Denominator = workingCopy.Denominator
+		decString = string(dbVal)
+	case string:
+		decString = dbVal
+	case float64:
+		decString = strconv.FormatFloat(dbVal, 'f', -1, 64)
+	default:
+		return fmt.Errorf("money: cannot scan %T into basis points", src)
+	}
 
+	workingCopy, err := ParseBasisPointsDecimal(decString)
+	if err != nil {
+		return err
 	}
 
+	*bps = *workingCopy
+
 	return nil
 
 }
 
-//DecimalString returns a normal decimal
+/*
+DecimalString returns the ratio as a plain decimal, e.g. 0.0275 or 1.25.
+Ratios that don't end as a decimal, such as thirds, are rounded to twelve
+places.
+*/
 func (bps *BasisPoints) DecimalString() string {
 
-	if bps.Numerator == 0 {
+	if bps.IsZero() {
 		return "0"
 	}
 
-	sb := strings.Builder{}
-	sb.WriteString("0.")
+	return formatRatio(bps.Rat())
 
-	numer := strconv.Itoa(bps.Numerator)
+}
 
-	expectedLen := int(math.Log10(float64(bps.Denominator)))
+// formatRatio writes a fraction as a plain decimal without trailing zeros,
+// rounding fractions that don't end as a decimal to twelve places.
+func formatRatio(ratio *big.Rat) string {
 
-	leftPadding := expectedLen - len(numer)
+	places, exact := ratioPlaces(ratio)
+	if !exact {
+		places = inexactPlaces
+	}
 
-	if leftPadding > 0 {
-		sb.WriteString(strings.Repeat("0", leftPadding))
+	decString := ratio.FloatString(places)
+	if strings.Contains(decString, ".") {
+		decString = strings.TrimRight(strings.TrimRight(decString, "0"), ".")
 	}
 
-	//strip right padded zeros
-	numer = strings.TrimRight(numer, "0")
+	return decString
 
-	sb.WriteString(numer)
+}
 
-	return sb.String()
+// inexactPlaces is the precision of ratios that don't end as a decimal.
+const inexactPlaces = 12
+
+/*
+ratioPlaces returns the number of decimal places needed to write a ratio
+exactly, or false if it repeats forever.
+*/
+func ratioPlaces(ratio *big.Rat) (int, bool) {
+
+	denom := new(big.Int).Set(ratio.Denom())
+	mod := new(big.Int)
+
+	places := make(map[int64]int)
+	for _, factor := range []int64{2, 5} {
+		divisor := big.NewInt(factor)
+		for {
+			quo, rem := new(big.Int).QuoRem(denom, divisor, mod)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = quo
+			places[factor]++
+		}
+	}
+
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+
+	if places[2] > places[5] {
+		return places[2], true
+	}
+
+	return places[5], true
 
 }
 
+// hasBasisPointDenominator reports whether the denominator is a power of ten
+// of at least 10,000, which the digit arithmetic in String and PercentString
+// relies on.
+func (bps *BasisPoints) hasBasisPointDenominator() bool {
+
+	denom := bps.Denominator
+	if denom < BpsPerWhole {
+		return false
+	}
+
+	for denom%10 == 0 {
+		denom /= 10
+	}
+
+	return denom == 1
+
+}
+
+// String returns the ratio in basis points, e.g. 275 or 254.5.
 func (bps *BasisPoints) String() string {
+	if !bps.hasBasisPointDenominator() {
+		return formatRatio(new(big.Rat).Mul(bps.Rat(), big.NewRat(BpsPerWhole, 1)))
+	}
 	if bps.Denominator > BpsPerWhole {
 		wholePoints := bps.WholeBasisPoints()
 		fracPoints := bps.FractionalBasisPoints()
@@ -276,68 +392,24 @@ func (bps *BasisPoints) String() string {
 	return strconv.Itoa(bps.Numerator)
 }
 
-//LTE returns true if the argument is less than or equal to the receiver
+//LTE returns true if the receiver is less than or equal to the argument
 func (bps *BasisPoints) LTE(arg *BasisPoints) bool {
-
-	rcvNum := bps.Numerator
-	argNum := arg.Numerator
-
-	if arg.Denominator > bps.Denominator {
-		rcvNum *= (arg.Denominator / bps.Denominator)
-	} else if arg.Denominator < bps.Denominator {
-		argNum *= (bps.Denominator / arg.Denominator)
-	}
-
-	return argNum >= rcvNum
-
+	return bps.Rat().Cmp(arg.Rat()) <= 0
 }
 
-//LT returns true if the argument is less than the receiver
+//LT returns true if the receiver is less than the argument
 func (bps *BasisPoints) LT(arg *BasisPoints) bool {
-
-	rcvNum := bps.Numerator
-	argNum := arg.Numerator
-
-	if arg.Denominator > bps.Denominator {
-		rcvNum *= (arg.Denominator / bps.Denominator)
-	} else if arg.Denominator < bps.Denominator {
-		argNum *= (bps.Denominator / arg.Denominator)
-	}
-
-	return argNum > rcvNum
-
+	return bps.Rat().Cmp(arg.Rat()) < 0
 }
 
 //GT returns true if the reciever is greater than the argument
 func (bps *BasisPoints) GT(arg *BasisPoints) bool {
-
-	rcvNum := bps.Numerator
-	argNum := arg.Numerator
-
-	if arg.Denominator > bps.Denominator {
-		rcvNum *= (arg.Denominator / bps.Denominator)
-	} else if arg.Denominator < bps.Denominator {
-		argNum *= (bps.Denominator / arg.Denominator)
-	}
-
-	return argNum < rcvNum
-
+	return bps.Rat().Cmp(arg.Rat()) > 0
 }
 
-//GTE returns true if the argument is greater than or equal to the receiver
+//GTE returns true if the receiver is greater than or equal to the argument
 func (bps *BasisPoints) GTE(arg *BasisPoints) bool {
-
-	rcvNum := bps.Numerator
-	argNum := arg.Numerator
-
-	if arg.Denominator > bps.Denominator {
-		rcvNum *= (arg.Denominator / bps.Denominator)
-	} else if arg.Denominator < bps.Denominator {
-		argNum *= (bps.Denominator / arg.Denominator)
-	}
-
-	return argNum <= rcvNum
-
+	return bps.Rat().Cmp(arg.Rat()) >= 0
 }
 
 // WholeBasisPoints returns whole basis points
@@ -365,6 +437,10 @@ func (bps *BasisPoints) FractionalBasisPoints() int {
 //PercentString returns the value as a percentage.
 func (bps *BasisPoints) PercentString() string {
 
+	if !bps.hasBasisPointDenominator() {
+		return formatRatio(new(big.Rat).Mul(bps.Rat(), big.NewRat(PctPointsPerWhole, 1))) + "%"
+	}
+
 	wholePoints := float64(bps.WholeBasisPoints())
 
 	percentagePoints := int(math.Floor(wholePoints / 100))
@@ -397,3 +473,227 @@ func (bps *BasisPoints) PercentString() string {
 
 	return sb.String()
 }
+
+// Add returns the sum of the ratios. It panics if the result overflows.
+func (bps *BasisPoints) Add(arg *BasisPoints) *BasisPoints {
+	return mustRatio(new(big.Rat).Add(bps.Rat(), arg.Rat()))
+}
+
+// Sub returns the receiver less the argument. It panics if the result
+// overflows.
+func (bps *BasisPoints) Sub(arg *BasisPoints) *BasisPoints {
+	return mustRatio(new(big.Rat).Sub(bps.Rat(), arg.Rat()))
+}
+
+// Mult returns the product of the ratios, e.g. a 50% share of a 3% fee is
+// 1.5%. It panics if the result overflows.
+func (bps *BasisPoints) Mult(arg *BasisPoints) *BasisPoints {
+	return mustRatio(new(big.Rat).Mul(bps.Rat(), arg.Rat()))
+}
+
+// Invert returns the reciprocal of the ratio. It panics if the ratio is
+// zero or the result overflows.
+func (bps *BasisPoints) Invert() *BasisPoints {
+
+	if bps.IsZero() {
+		panic(ErrDivideByZero)
+	}
+
+	return mustRatio(new(big.Rat).Inv(bps.Rat()))
+
+}
+
+/*
+Compound returns the total rate of applying the rate for the given number of
+periods, each on top of the last, e.g. 10% compounded twice is 21%. Negative
+periods undo the compounding.
+*/
+func (bps *BasisPoints) Compound(periods int) *BasisPoints {
+
+	growth := new(big.Rat).Add(big.NewRat(1, 1), bps.Rat())
+
+	if periods < 0 {
+		if growth.Sign() == 0 {
+			panic(ErrDivideByZero)
+		}
+		growth.Inv(growth)
+		periods = -periods
+	}
+
+	total := big.NewRat(1, 1)
+	for i := 0; i < periods; i++ {
+		total.Mul(total, growth)
+	}
+
+	return mustRatio(total.Sub(total, big.NewRat(1, 1)))
+
+}
+
+/*
+Reduce returns the ratio in lowest terms, e.g. 2500/10000 becomes 1/4. The
+arithmetic methods keep denominators of at least 10,000 where they can, so
+the basis point formats work on their results; Reduce is for storage and
+display of the exact fraction.
+*/
+func (bps *BasisPoints) Reduce() *BasisPoints {
+
+	if bps.Denominator == 0 {
+		return &BasisPoints{}
+	}
+
+	ratio := bps.Rat()
+
+	return &BasisPoints{Numerator: int(ratio.Num().Int64()), Denominator: int(ratio.Denom().Int64())}
+
+}
+
+// Apply multiplies the amount by the ratio, rounding to the amount's
+// precision with the given mode.
+func (bps *BasisPoints) Apply(amount *Currency, mode RoundingMode) *Currency {
+	return amount.MultBPSRounded(bps, mode)
+}
+
+/*
+ratioToBasisPoints converts an exact fraction to basis points. Fractions that
+end as a decimal are kept over a power of ten of at least 10,000, others are
+kept in lowest terms.
+*/
+func ratioToBasisPoints(ratio *big.Rat) (*BasisPoints, error) {
+
+	numerator, denominator := ratio.Num(), ratio.Denom()
+
+	if places, exact := ratioPlaces(ratio); exact && places <= maxDecimalPlaces {
+		if minDecPlaces := int(math.Log10(BpsPerWhole)); places < minDecPlaces {
+			places = minDecPlaces
+		}
+		denominator = bigPow10(places)
+		numerator = new(big.Int).Mul(ratio.Num(), denominator)
+		numerator.Quo(numerator, ratio.Denom())
+	}
+
+	numer, err := toInt(numerator)
+	if err != nil {
+		return nil, err
+	}
+
+	denom, err := toInt(denominator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BasisPoints{Numerator: numer, Denominator: denom}, nil
+
+}
+
+func mustRatio(ratio *big.Rat) *BasisPoints {
+
+	result, err := ratioToBasisPoints(ratio)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+
+}
+
+// BasisPointsJSONFormat selects how basis points are written to JSON.
+type BasisPointsJSONFormat int32
+
+// BasisPointsJSONFormat constants.
+const (
+	BasisPointsJSONObject BasisPointsJSONFormat = iota //{"Numerator":275,"Denominator":10000}
+	BasisPointsJSONString                              //"0.0275", or "1/3" for ratios that don't end as a decimal
+)
+
+var bpsJSONFormat = int32(BasisPointsJSONObject)
+
+/*
+SetBasisPointsJSONFormat sets the format MarshalJSON writes and returns the
+previous one. UnmarshalJSON reads every format regardless, so clients can be
+moved over one at a time. Set it at startup.
+*/
+func SetBasisPointsJSONFormat(format BasisPointsJSONFormat) BasisPointsJSONFormat {
+	return BasisPointsJSONFormat(atomic.SwapInt32(&bpsJSONFormat, int32(format)))
+}
+
+/*
+MarshalJSON writes the format set with SetBasisPointsJSONFormat. Both keep
+the exact fraction: the string format writes a decimal, e.g. "0.0275", when
+that reads back as the same fraction, and "numerator/denominator" otherwise.
+*/
+func (bps BasisPoints) MarshalJSON() ([]byte, error) {
+
+	if BasisPointsJSONFormat(atomic.LoadInt32(&bpsJSONFormat)) != BasisPointsJSONString {
+		return json.Marshal(struct {
+			Numerator   int
+			Denominator int
+		}{bps.Numerator, bps.Denominator})
+	}
+
+	if bps.Denominator == 0 {
+		return json.Marshal("0")
+	}
+
+	decString := bps.DecimalString()
+	if parsed, err := ParseBasisPointsDecimal(decString); err == nil && *parsed == bps {
+		return json.Marshal(decString)
+	}
+
+	return json.Marshal(strconv.Itoa(bps.Numerator) + "/" + strconv.Itoa(bps.Denominator))
+
+}
+
+/*
+UnmarshalJSON reads both formats MarshalJSON writes, and plain JSON
+numbers.
+*/
+func (bps *BasisPoints) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '{' {
+		var raw struct {
+			Numerator   int
+			Denominator int
+		}
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return err
+		}
+		bps.Numerator, bps.Denominator = raw.Numerator, raw.Denominator
+		return nil
+	}
+
+	var raw string
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return err
+		}
+	} else {
+		raw = string(b)
+	}
+
+	if tokens := strings.Split(raw, "/"); len(tokens) == 2 {
+		numer, err := strconv.Atoi(strings.TrimSpace(tokens[0]))
+		if err != nil {
+			return fmt.Errorf("%w: %q is not a ratio", ErrInvalidAmount, raw)
+		}
+		denom, err := strconv.Atoi(strings.TrimSpace(tokens[1]))
+		if err != nil || denom <= 0 {
+			return fmt.Errorf("%w: %q is not a ratio", ErrInvalidAmount, raw)
+		}
+		bps.Numerator, bps.Denominator = numer, denom
+		return nil
+	}
+
+	parsed, err := ParseBasisPointsDecimal(raw)
+	if err != nil {
+		return err
+	}
+
+	*bps = *parsed
+
+	return nil
+
+}
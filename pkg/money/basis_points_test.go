@@ -1,6 +1,8 @@
 package money
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -289,3 +291,144 @@ func TestTinyFractionalBasisPointsParsing(t *testing.T) {
 	assert.Equal("0.000355", bps.DecimalString())
 
 }
+
+func TestBasisPointsDecimalParsing(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tests := []struct {
+		input       string
+		numerator   int
+		denominator int
+		decimal     string
+	}{
+		{"1.25", 12500, 10000, "1.25"},
+		{"1", 10000, 10000, "1"},
+		{"12", 120000, 10000, "12"},
+		{"0", 0, 10000, "0"},
+		{".5", 5000, 10000, "0.5"},
+		{"-0.0275", -275, 10000, "-0.0275"},
+		{"2.000125", 2000125, 1000000, "2.000125"},
+		{" 0.10 ", 1000, 10000, "0.1"},
+	}
+
+	for _, test := range tests {
+		bps, err := ParseBasisPointsDecimal(test.input)
+		assert.NoError(err, test.input)
+		assert.Equal(test.numerator, bps.Numerator, test.input)
+		assert.Equal(test.denominator, bps.Denominator, test.input)
+		assert.Equal(test.decimal, bps.DecimalString(), test.input)
+	}
+
+	for _, input := range []string{"", ".", "-", "1.2.3", "abc", "1,5"} {
+		_, err := ParseBasisPointsDecimal(input)
+		assert.True(errors.Is(err, ErrInvalidAmount), input)
+	}
+
+	var scanned BasisPoints
+	assert.NoError(scanned.Scan([]byte("1.5")))
+	assert.Equal("1.5", scanned.DecimalString())
+	assert.NoError(scanned.Scan("0.0275"))
+	assert.Equal(275, scanned.Numerator)
+	assert.NoError(scanned.Scan(nil))
+	assert.Equal(BasisPoints{}, scanned)
+
+}
+
+func TestBasisPointsArithmetic(t *testing.T) {
+
+	assert := assert.New(t)
+
+	fee := NewBasisPoints(275)
+	surcharge := NewFractionalBasisPoints(5, 100000)
+
+	assert.Equal(&BasisPoints{Numerator: 2755, Denominator: 100000}, fee.Add(surcharge))
+	assert.Equal(&BasisPoints{Numerator: 2745, Denominator: 100000}, fee.Sub(surcharge))
+	assert.Equal("-0.0275", NewBasisPoints(0).Sub(fee).DecimalString())
+
+	//half of 2.75% is 1.375%
+	assert.Equal(&BasisPoints{Numerator: 1375, Denominator: 100000}, fee.Mult(NewBasisPoints(5000)))
+
+	assert.Equal(&BasisPoints{Numerator: 40000, Denominator: 10000}, NewBasisPoints(2500).Invert())
+	assert.Equal(&BasisPoints{Numerator: 1, Denominator: 3}, NewBasisPoints(30000).Invert())
+	assert.Equal("0.333333333333", NewBasisPoints(30000).Invert().DecimalString())
+	assert.Panics(func() { NewBasisPoints(0).Invert() })
+
+	assert.Equal(&BasisPoints{Numerator: 1, Denominator: 4}, NewBasisPoints(2500).Reduce())
+	assert.Equal(&BasisPoints{Numerator: 11, Denominator: 400}, fee.Reduce())
+
+	assert.Equal("0.21", NewBasisPoints(1000).Compound(2).DecimalString())
+	assert.Equal("0.1", NewBasisPoints(2100).Compound(0).Add(NewBasisPoints(1000)).DecimalString())
+	assert.Equal("-0.173553719008", NewBasisPoints(1000).Compound(-2).DecimalString())
+	assert.True(NewBasisPoints(1000).Compound(2).Compound(-1).LTE(NewBasisPoints(2100)))
+
+	//results that aren't over a power of ten still format and compare
+	third := NewBasisPoints(30000).Invert()
+	assert.Equal("3333.333333333333", third.String())
+	assert.Equal("33.333333333333%", third.PercentString())
+	assert.Equal("2500", NewBasisPoints(2500).Reduce().String())
+	assert.Equal("25%", NewBasisPoints(2500).Reduce().PercentString())
+	assert.Equal("10000", NewBasisPoints(300).Invert().Mult(NewBasisPoints(300)).String())
+	assert.Equal("333333.333333333333", NewBasisPoints(300).Invert().String())
+
+	twoSevenths := NewFractionalBasisPoints(2, 7)
+	assert.True(third.GT(twoSevenths))
+	assert.True(third.GTE(twoSevenths))
+	assert.False(third.LT(twoSevenths))
+	assert.False(third.LTE(twoSevenths))
+	assert.True(twoSevenths.LT(third))
+	assert.True(third.LTE(NewFractionalBasisPoints(2, 6)))
+	assert.Error(ValidateBasisPointsSequential(third, twoSevenths))
+	assert.NoError(ValidateBasisPointsSequential(twoSevenths, third, NewBasisPoints(3334)))
+
+	assert.Equal("2.75", fee.Apply(ParseCurrency(CurrencyCodeUSD, "100.00"), RoundNearest).FormatCurrency())
+	assert.Equal("0.04", fee.Apply(ParseCurrency(CurrencyCodeUSD, "1.50"), RoundNearest).FormatCurrency())
+	assert.Equal("0.05", fee.Apply(ParseCurrency(CurrencyCodeUSD, "1.50"), RoundUp).FormatCurrency())
+
+}
+
+func TestBasisPointsJSON(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tests := []struct {
+		bps    BasisPoints
+		expect string
+	}{
+		{BasisPoints{Numerator: 275, Denominator: 10000}, `"0.0275"`},
+		{BasisPoints{Numerator: 2855, Denominator: 100000}, `"0.02855"`},
+		{BasisPoints{Numerator: 12500, Denominator: 10000}, `"1.25"`},
+		{BasisPoints{Numerator: 1, Denominator: 3}, `"1/3"`},
+		{BasisPoints{Numerator: 2500, Denominator: 100000}, `"2500/100000"`},
+	}
+
+	//the object format is the default
+	b, err := json.Marshal(BasisPoints{Numerator: 1, Denominator: 3})
+	assert.NoError(err)
+	assert.Equal(`{"Numerator":1,"Denominator":3}`, string(b))
+
+	var decoded BasisPoints
+	assert.NoError(json.Unmarshal(b, &decoded))
+	assert.Equal(BasisPoints{Numerator: 1, Denominator: 3}, decoded)
+
+	defer SetBasisPointsJSONFormat(SetBasisPointsJSONFormat(BasisPointsJSONString))
+
+	for _, test := range tests {
+		b, err := json.Marshal(test.bps)
+		assert.NoError(err)
+		assert.Equal(test.expect, string(b))
+
+		var decoded BasisPoints
+		assert.NoError(json.Unmarshal(b, &decoded))
+		assert.Equal(test.bps, decoded)
+	}
+
+	assert.NoError(json.Unmarshal([]byte(`{"Numerator":275,"Denominator":10000}`), &decoded))
+	assert.Equal(BasisPoints{Numerator: 275, Denominator: 10000}, decoded)
+
+	assert.NoError(json.Unmarshal([]byte(`0.0275`), &decoded))
+	assert.Equal(BasisPoints{Numerator: 275, Denominator: 10000}, decoded)
+
+	assert.Error(json.Unmarshal([]byte(`"1/0"`), &decoded))
+
+}
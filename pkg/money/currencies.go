@@ -0,0 +1,171 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Currency errors.
+var (
+	ErrUnsupportedCurrency       = errors.New("unsupported currency")
+	ErrInvalidCurrencyDefinition = errors.New("invalid currency definition")
+	ErrInvalidAmount             = errors.New("invalid currency amount")
+	ErrCurrencyMismatch          = errors.New("currencies don't match")
+	ErrDivideByZero              = errors.New("division by zero")
+	ErrNoValues                  = errors.New("no values")
+)
+
+var (
+	currencyLock sync.RWMutex
+	numericCodes = make(map[string]string)
+)
+
+// cashIncrements are the smallest coins in circulation, in minor units, for
+// currencies that round cash payments. Override them with RegisterCurrency.
+var cashIncrements = map[string]int{
+	"AUD": 5,
+	"CAD": 5,
+	"CHF": 5,
+	"DKK": 50,
+	"NZD": 10,
+	"SEK": 100,
+}
+
+func init() {
+
+	for _, def := range iso4217Currencies {
+		if existing, ok := CurrencyDefinitionMap[def.Code]; ok {
+			//keep the hand tuned formatting of the original currencies
+			existing.Code = def.Code
+			existing.NumericCode = def.NumericCode
+			existing.Name = def.Name
+			CurrencyDefinitionMap[def.Code] = existing
+			continue
+		}
+		def.DecimalSeparator = "."
+		def.ThousandsSeparator = ","
+		CurrencyDefinitionMap[def.Code] = def
+	}
+
+	for code, def := range CurrencyDefinitionMap {
+		if def.Code == "" {
+			def.Code = code
+		}
+		if increment, ok := cashIncrements[code]; ok && def.CashIncrement == 0 {
+			def.CashIncrement = increment
+		}
+		CurrencyDefinitionMap[code] = def
+		if def.NumericCode != "" {
+			numericCodes[def.NumericCode] = code
+		}
+	}
+
+}
+
+/*
+RegisterCurrency adds a custom currency, such as a loyalty point or voucher
+unit, or replaces the definition of an existing one. The decimal separator
+defaults to a period. Register custom currencies at startup, before any
+amounts in them are created.
+*/
+func RegisterCurrency(def CurrencyDefinition) error {
+
+	if def.Code == "" || strings.ToUpper(def.Code) != def.Code || def.DecimalPlaces < 0 || def.DecimalPlaces > maxDecimalPlaces {
+		return ErrInvalidCurrencyDefinition
+	}
+
+	if def.DecimalSeparator == "" {
+		def.DecimalSeparator = "."
+	}
+
+	currencyLock.Lock()
+	defer currencyLock.Unlock()
+
+	if def.NumericCode != "" {
+		if owner, ok := numericCodes[def.NumericCode]; ok && owner != def.Code {
+			return fmt.Errorf("%w: numeric code %v already belongs to %v", ErrInvalidCurrencyDefinition, def.NumericCode, owner)
+		}
+	}
+
+	if existing, ok := CurrencyDefinitionMap[def.Code]; ok && existing.NumericCode != "" {
+		delete(numericCodes, existing.NumericCode)
+	}
+
+	CurrencyDefinitionMap[def.Code] = def
+	if def.NumericCode != "" {
+		numericCodes[def.NumericCode] = def.Code
+	}
+
+	return nil
+
+}
+
+// LookupCurrency returns the definition of a currency by its alphabetic code.
+func LookupCurrency(code string) (CurrencyDefinition, error) {
+
+	currencyLock.RLock()
+	def, ok := CurrencyDefinitionMap[code]
+	currencyLock.RUnlock()
+
+	if !ok {
+		return def, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, code)
+	}
+
+	return def, nil
+
+}
+
+/*
+LookupNumericCode returns the definition of a currency by its ISO 4217
+numeric code, as reported by payment terminals. Leading zeros are optional.
+*/
+func LookupNumericCode(numericCode string) (CurrencyDefinition, error) {
+
+	padded := strings.TrimSpace(numericCode)
+	if len(padded) < 3 {
+		padded = strings.Repeat("0", 3-len(padded)) + padded
+	}
+
+	currencyLock.RLock()
+	code, ok := numericCodes[padded]
+	def := CurrencyDefinitionMap[code]
+	currencyLock.RUnlock()
+
+	if !ok {
+		return def, fmt.Errorf("%w: numeric code %v", ErrUnsupportedCurrency, numericCode)
+	}
+
+	return def, nil
+
+}
+
+// resolveCurrency looks a currency up by either its alphabetic or numeric code.
+func resolveCurrency(code string) (CurrencyDefinition, error) {
+
+	if code != "" && strings.Trim(code, "0123456789") == "" {
+		return LookupNumericCode(code)
+	}
+
+	return LookupCurrency(code)
+
+}
+
+// CurrencyCodes returns the codes of all registered currencies in
+// alphabetical order.
+func CurrencyCodes() []string {
+
+	currencyLock.RLock()
+	codes := make([]string, 0, len(CurrencyDefinitionMap))
+	for code := range CurrencyDefinitionMap {
+		codes = append(codes, code)
+	}
+	currencyLock.RUnlock()
+
+	sort.Strings(codes)
+
+	return codes
+
+}
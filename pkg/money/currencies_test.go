@@ -0,0 +1,95 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCurrency(t *testing.T) {
+
+	assert := assert.New(t)
+
+	def, err := LookupCurrency("JPY")
+	assert.NoError(err)
+	assert.Equal("392", def.NumericCode)
+	assert.Equal(0, def.DecimalPlaces)
+	assert.Equal(".", def.DecimalSeparator)
+
+	def, err = LookupCurrency(CurrencyCodeEUR)
+	assert.NoError(err)
+	assert.Equal("978", def.NumericCode)
+	assert.Equal(",", def.DecimalSeparator)
+	assert.True(def.CurrencySymbolAfter)
+
+	def, err = LookupCurrency(CurrencyCodeBTC)
+	assert.NoError(err)
+	assert.Equal(8, def.DecimalPlaces)
+	assert.Empty(def.NumericCode)
+
+	_, err = LookupCurrency("XYZ")
+	assert.True(errors.Is(err, ErrUnsupportedCurrency))
+
+	assert.Panics(func() { (&Currency{CurrencyCode: "XYZ"}).CurrencyDefinition() })
+	assert.Equal(3, (&Currency{CurrencyCode: "KWD"}).CurrencyDefinition().DecimalPlaces)
+
+	assert.Contains(CurrencyCodes(), "ZAR")
+	assert.Contains(CurrencyCodes(), CurrencyCodeBCP)
+
+}
+
+func TestLookupNumericCode(t *testing.T) {
+
+	assert := assert.New(t)
+
+	def, err := LookupNumericCode("840")
+	assert.NoError(err)
+	assert.Equal(CurrencyCodeUSD, def.Code)
+
+	def, err = LookupNumericCode("36")
+	assert.NoError(err)
+	assert.Equal("AUD", def.Code)
+
+	_, err = LookupNumericCode("000")
+	assert.True(errors.Is(err, ErrUnsupportedCurrency))
+
+}
+
+func TestRegisterCurrency(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(RegisterCurrency(CurrencyDefinition{Code: "TKN", Name: "Ticket Credit", DecimalPlaces: 1}))
+
+	amount := ParseCurrency("TKN", "12.5")
+	assert.Equal("TKN", amount.CurrencyCode)
+	assert.Equal("12.5", amount.FormatCurrency())
+
+	err := RegisterCurrency(CurrencyDefinition{Code: "TKX", NumericCode: "840"})
+	assert.True(errors.Is(err, ErrInvalidCurrencyDefinition))
+
+	assert.Equal(ErrInvalidCurrencyDefinition, RegisterCurrency(CurrencyDefinition{Code: "tkn"}))
+	assert.Equal(ErrInvalidCurrencyDefinition, RegisterCurrency(CurrencyDefinition{Code: "TKN", DecimalPlaces: 19}))
+
+}
+
+func TestParseNumericCodeCurrencies(t *testing.T) {
+
+	assert := assert.New(t)
+
+	result := ParseNumericCode("978", "000000100099")
+	assert.Equal(CurrencyCodeEUR, result.CurrencyCode)
+	assert.Equal("1.000,99", result.FormatCurrency())
+
+	result = ParseNumericCode("JPY", "000000001500")
+	assert.Equal(1500, result.Integer)
+	assert.Equal(0, result.Decimal)
+
+	result = ParseNumericCode("048", "12345")
+	assert.Equal("BHD", result.CurrencyCode)
+	assert.Equal("12.345", result.FormatCurrency())
+
+	assert.Panics(func() { ParseNumericCode("999", "100") })
+
+}
@@ -1,14 +1,14 @@
 package money
 
 import (
-	"bytes"
 	"database/sql/driver"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -19,9 +19,16 @@ type RoundingMode byte
 
 //RoundingMode constants.
 const (
-	RoundUp      RoundingMode = iota //always round up
-	RoundDown                        //always round down
-	RoundNearest                     //round >= .5 up and < .5 down
+	RoundUp           RoundingMode = iota //toward positive infinity, except in DivideInt, which keeps its original rounding
+	RoundDown                             //toward negative infinity, except in DivideInt, which keeps its original rounding
+	RoundNearest                          //round >= .5 away from zero and < .5 toward zero, except in DivideInt
+	RoundHalfEven                         //round to nearest, .5 to the nearest even digit (banker's rounding)
+	RoundHalfDown                         //round > .5 away from zero and <= .5 toward zero
+	RoundTowardZero                       //truncate
+	RoundAwayFromZero                     //always round away from zero
+	RoundCeiling                          //toward positive infinity, so -1.5 rounds to -1; RoundUp outside DivideInt
+	RoundFloor                            //toward negative infinity, so -1.5 rounds to -2; RoundDown outside DivideInt
+	RoundHalfUp                           //round >= .5 away from zero; RoundNearest outside DivideInt
 )
 
 /*
@@ -39,11 +46,15 @@ type Currency struct {
 CurrencyDefinition models locale specific characteristics of a currency.
 */
 type CurrencyDefinition struct {
+	Code                string //alphabetic code, e.g. USD
+	NumericCode         string //ISO 4217 numeric code, e.g. 840, empty for non ISO currencies
+	Name                string
 	DecimalPlaces       int
 	DecimalSeparator    string
 	CurrencySymbol      string
 	ThousandsSeparator  string
 	CurrencySymbolAfter bool
+	CashIncrement       int //smallest cash denomination in minor units, e.g. 5 for CHF, zero if cash uses the minor unit
 }
 
 // Currency code constants.
@@ -58,10 +69,12 @@ const (
 	CurrencyCodeDefault = CurrencyCodeUSD
 )
 
-// CurrencyDefinitionMap includes configuration for various currencies.
+// CurrencyDefinitionMap includes configuration for various currencies. It's
+// filled in with the full ISO 4217 table at startup; use RegisterCurrency to
+// add custom units.
 var CurrencyDefinitionMap = map[string]CurrencyDefinition{
 	CurrencyCodeBCP: CurrencyDefinition{DecimalPlaces: 0, DecimalSeparator: "."},
-	CurrencyCodeBTC: CurrencyDefinition{DecimalPlaces: 8, DecimalSeparator: "."},
+	CurrencyCodeBTC: CurrencyDefinition{Name: "Bitcoin", DecimalPlaces: 8, DecimalSeparator: ".", CurrencySymbol: "₿"},
 	CurrencyCodeCAD: CurrencyDefinition{DecimalPlaces: 2, DecimalSeparator: ".", ThousandsSeparator: ",", CurrencySymbol: "$"},
 	CurrencyCodeUSD: CurrencyDefinition{DecimalPlaces: 2, DecimalSeparator: ".", ThousandsSeparator: ",", CurrencySymbol: "$"},
 	CurrencyCodeGBP: CurrencyDefinition{DecimalPlaces: 2, DecimalSeparator: ".", ThousandsSeparator: ",", CurrencySymbol: "£"},
@@ -69,27 +82,36 @@ var CurrencyDefinitionMap = map[string]CurrencyDefinition{
 }
 
 // CurrencyDefinition returns the currency type definition. If it's empty, it
-// will return the default.
+// will return the default. It panics for unsupported currencies; use
+// LookupCurrency to get an error instead.
 func (base Currency) CurrencyDefinition() CurrencyDefinition {
-	var code string
 
-	if base.CurrencyCode == "" {
-		code = CurrencyCodeDefault
-
-	} else {
-		code = base.CurrencyCode
+	def, err := base.definition()
+	if err != nil {
+		panic(err)
 	}
 
-	def, ok := CurrencyDefinitionMap[code]
-	if !ok {
-		panic("unsupported currency: " + code)
+	return def
+
+}
+
+// definition looks up the currency's definition, using the default currency
+// if the code is empty.
+func (base Currency) definition() (CurrencyDefinition, error) {
+
+	if base.CurrencyCode == "" {
+		return LookupCurrency(CurrencyCodeDefault)
 	}
 
-	return def
+	return LookupCurrency(base.CurrencyCode)
+
 }
 
 /*
 Value implements the valuer interface in order to support sql serialization.
+The value is a plain decimal with a period separator for a numeric column, or
+with the currency's own separator under SQLLegacy; keep the currency code in
+a companion column and read it back with CurrencyCodeScanner.
 */
 func (base Currency) Value() (driver.Value, error) {
 
@@ -97,18 +119,19 @@ func (base Currency) Value() (driver.Value, error) {
 		return "0", nil
 	}
 
-	results := strconv.Itoa(base.Integer)
-
-	def := base.CurrencyDefinition()
+	separator := "."
+	if SQLFormat(atomic.LoadInt32(&sqlFormat)) == SQLLegacy {
+		separator = base.CurrencyDefinition().DecimalSeparator
+	}
 
-	results += string(def.DecimalSeparator)
+	results := strconv.Itoa(base.Integer) + separator
 
 	rawDecimal := strconv.Itoa(base.Decimal)
 
-	decimalPlaces := int(math.Log10(float64(base.DecimalDenominator)))
+	places := decimalPlaces(base.DecimalDenominator)
 
-	if len(rawDecimal) < decimalPlaces {
-		rawDecimal = strings.Repeat("0", decimalPlaces-len(rawDecimal)) + rawDecimal
+	if len(rawDecimal) < places {
+		rawDecimal = strings.Repeat("0", places-len(rawDecimal)) + rawDecimal
 	}
 
 	results += rawDecimal
@@ -122,21 +145,35 @@ func (base Currency) Value() (driver.Value, error) {
 }
 
 /*
-Scan implements the scan interface in order to support sql serialization.
+Scan implements the scan interface in order to support sql serialization. A
+plain decimal keeps the receiver's currency code, which defaults to USD, so
+it can be combined with CurrencyCodeScanner. Values holding their own code,
+either as text such as EUR 1234.50 or as a composite such as (1234.50,EUR),
+set it.
 */
 func (base *Currency) Scan(src interface{}) error {
 
+	var value string
+
 	switch dbVal := src.(type) {
+	case nil:
+		*base = Currency{CurrencyCode: base.CurrencyCode}
+		return nil
 	case []uint8:
-		floatString := string(dbVal)
-		tmpCur := ParseCurrency(CurrencyCodeUSD, floatString)
-		base.Integer = tmpCur.Integer
-		base.CurrencyCode = tmpCur.CurrencyCode
-		base.Decimal = tmpCur.Decimal
-		base.DecimalDenominator = tmpCur.DecimalDenominator
-		base.Negative = tmpCur.Negative
+		value = string(dbVal)
+	case string:
+		value = dbVal
+	default:
+		return fmt.Errorf("money: cannot scan %T into a currency", src)
+	}
+
+	scanned, err := scanCurrency(value, base.CurrencyCode)
+	if err != nil {
+		return err
 	}
 
+	*base = *scanned
+
 	return nil
 
 }
@@ -159,7 +196,7 @@ func (base *Currency) ComputeDecimal() *Currency {
 
 	def := base.CurrencyDefinition()
 
-	targetDenominator := int(math.Pow10(def.DecimalPlaces))
+	targetDenominator := pow10(def.DecimalPlaces)
 
 	if base.DecimalDenominator == 0 {
 		return base
@@ -169,7 +206,7 @@ func (base *Currency) ComputeDecimal() *Currency {
 		return base
 	}
 
-	base.Decimal = base.Decimal * targetDenominator / base.DecimalDenominator
+	base.Decimal = mulInt(base.Decimal, targetDenominator) / base.DecimalDenominator
 
 	return base
 
@@ -186,57 +223,74 @@ func (base *Currency) decimalMultiplier() int {
 
 	def := base.CurrencyDefinition()
 
-	return int(math.Pow10(def.DecimalPlaces))
+	return pow10(def.DecimalPlaces)
 
 }
 
-func (base *Currency) assertCurrencyMatch(operand *Currency) {
+// checkOperands returns an error if either currency is unsupported or the
+// currencies differ. Zero amounts match any currency.
+func (base *Currency) checkOperands(operand *Currency) error {
+
+	if _, err := base.definition(); err != nil {
+		return err
+	}
+
+	if _, err := operand.definition(); err != nil {
+		return err
+	}
 
 	if base.IsZero() || operand.IsZero() {
-		return
+		return nil
 	}
 
 	if base.CurrencyCode != operand.CurrencyCode {
-		panic(base.CurrencyCode + " and " + operand.CurrencyCode + " are different currencies")
+		return fmt.Errorf("%w: %v and %v", ErrCurrencyMismatch, base.CurrencyCode, operand.CurrencyCode)
 	}
 
+	return nil
+
 }
 
 // normalizeCurrency converts zero amounts to match the currency code of
 // non-zero amounts for operations on multiple currencies.
-func (base *Currency) normalizeCurrency(operand *Currency) {
-	base.assertCurrencyMatch(operand)
+func (base *Currency) normalizeCurrency(operand *Currency) error {
+
+	if err := base.checkOperands(operand); err != nil {
+		return err
+	}
 
 	if base.IsZero() {
 		if !operand.IsZero() {
 			base.CurrencyCode = operand.CurrencyCode
 			if base.DecimalDenominator == 0 {
 				def := operand.CurrencyDefinition()
-				base.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+				base.DecimalDenominator = pow10(def.DecimalPlaces)
 			}
 		}
 	} else {
 		if operand.IsZero() {
 			operand.CurrencyCode = base.CurrencyCode
 			def := operand.CurrencyDefinition()
-			operand.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+			operand.DecimalDenominator = pow10(def.DecimalPlaces)
 		}
 	}
 
 	if !base.IsZero() && base.DecimalDenominator == 0 {
 		def := operand.CurrencyDefinition()
-		base.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+		base.DecimalDenominator = pow10(def.DecimalPlaces)
 	}
 
 	if !operand.IsZero() && operand.DecimalDenominator == 0 {
 		def := operand.CurrencyDefinition()
-		operand.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+		operand.DecimalDenominator = pow10(def.DecimalPlaces)
 	}
 
+	return nil
+
 }
 
 //toScaledInts converts the given operands to integers in the same space
-func toScaledInts(op1, op2 *Currency) (r1 int, r2 int, denom int) {
+func toScaledInts(op1, op2 *Currency) (r1 *big.Int, r2 *big.Int, denom int) {
 
 	if op1.DecimalDenominator == 0 {
 		op1.normalize()
@@ -250,60 +304,71 @@ func toScaledInts(op1, op2 *Currency) (r1 int, r2 int, denom int) {
 		denom = op2.DecimalDenominator
 	}
 
-	r1 = op1.toScaledInt()
-	r2 = op2.toScaledInt()
-
-	if denom > op1.DecimalDenominator {
-		opPlaces := math.Log10(float64(op1.DecimalDenominator))
-		denomPlaces := math.Log10(float64(denom))
-		diff := int(denomPlaces - opPlaces)
-		r1 *= int(math.Pow10(diff))
-
-	}
-
-	if denom > op2.DecimalDenominator {
-		opPlaces := math.Log10(float64(op2.DecimalDenominator))
-		denomPlaces := math.Log10(float64(denom))
-		diff := int(denomPlaces - opPlaces)
-		r2 *= int(math.Pow10(diff))
-	}
+	r1 = rescale(op1.scaled(), op1.DecimalDenominator, denom)
+	r2 = rescale(op2.scaled(), op2.DecimalDenominator, denom)
 
 	return r1, r2, denom
 }
 
 /*
-Add adds the operand to base and returns the result.
+Add adds the operand to base and returns the result. It panics if the
+currencies differ or the result overflows.
 */
 func (base *Currency) Add(operand *Currency) *Currency {
+	return must(base.AddChecked(operand))
+}
+
+/*
+AddChecked adds the operand to base and returns the result, or an error if
+the currencies differ or the result overflows.
+*/
+func (base *Currency) AddChecked(operand *Currency) (*Currency, error) {
+
+	if err := base.checkOperands(operand); err != nil {
+		return nil, err
+	}
 
 	baseInt, opInt, denom := toScaledInts(base, operand)
 
-	sumScaled := baseInt + opInt
+	sumScaled := new(big.Int).Add(baseInt, opInt)
 
 	currency := base.CurrencyCode
 	if currency == "" {
 		currency = operand.CurrencyCode
 	}
 
-	return fromScaledInt(currency, sumScaled, denom)
+	return fromScaledChecked(currency, sumScaled, denom)
 
 }
 
 /*
-Subtract subtracts the operand from base and returns the result.
+Subtract subtracts the operand from base and returns the result. It panics if
+the currencies differ or the result overflows.
 */
 func (base *Currency) Subtract(operand *Currency) *Currency {
+	return must(base.SubtractChecked(operand))
+}
+
+/*
+SubtractChecked subtracts the operand from base and returns the result, or an
+error if the currencies differ or the result overflows.
+*/
+func (base *Currency) SubtractChecked(operand *Currency) (*Currency, error) {
+
+	if err := base.checkOperands(operand); err != nil {
+		return nil, err
+	}
 
 	baseInt, opInt, denom := toScaledInts(base, operand)
 
-	diffScaled := baseInt - opInt
+	diffScaled := new(big.Int).Sub(baseInt, opInt)
 
 	currency := base.CurrencyCode
 	if currency == "" {
 		currency = operand.CurrencyCode
 	}
 
-	return fromScaledInt(currency, diffScaled, denom)
+	return fromScaledChecked(currency, diffScaled, denom)
 
 }
 
@@ -337,15 +402,7 @@ func (base *Currency) Equals(operand *Currency) bool {
 		return false
 	}
 
-	if base.Integer != operand.Integer {
-		return false
-	}
-
-	if base.Decimal != operand.Decimal {
-		return false
-	}
-
-	return true
+	return compareScaled(base, operand) == 0
 
 }
 
@@ -357,109 +414,183 @@ func (base *Currency) NotEquals(operand *Currency) bool {
 }
 
 /*
-MultBPS multiplies currency by the given percentage or basis points.
+MultBPS multiplies currency by the given percentage or basis points,
+rounding up.
 */
 func (base *Currency) MultBPS(bps *BasisPoints) *Currency {
 
-	return base.MultFloat(bps.Float64(), RoundUp)
+	return base.MultBPSRounded(bps, RoundUp)
 
 }
 
 /*
-Pct multiplies itself by the given percentage and returns the result.
+MultBPSRounded multiplies currency by the given percentage or basis points,
+rounding the exact result to the receiver's precision with the given mode.
 */
-func (base *Currency) Pct(operand float64) *Currency {
-
-	decimalMultiplier := base.decimalMultiplier()
-
-	result := Currency{CurrencyCode: base.CurrencyCode}
+func (base *Currency) MultBPSRounded(bps *BasisPoints, mode RoundingMode) *Currency {
 
-	baseFloat := float64(base.Integer) + float64(base.Decimal)/float64(decimalMultiplier)
+	return must(base.mulRat(bps.Rat(), mode))
 
-	resultFloat := baseFloat * (operand / float64(decimalMultiplier))
+}
 
-	resultInt := int(resultFloat * float64(decimalMultiplier))
+/*
+Pct multiplies itself by the given percentage and returns the result,
+truncated to the receiver's precision.
+*/
+func (base *Currency) Pct(operand float64) *Currency {
 
-	result.Integer = resultInt / decimalMultiplier
-	result.Decimal = resultInt % decimalMultiplier
+	return base.PctRounded(operand, RoundTowardZero)
 
-	return &result
 }
 
-func intAbs(val int) int {
-	if val < 0 {
-		return 0 - val
+/*
+PctRounded multiplies itself by the given percentage and returns the result,
+rounded to the receiver's precision with the given mode.
+*/
+func (base *Currency) PctRounded(operand float64, mode RoundingMode) *Currency {
+
+	pct, err := floatRat(operand)
+	if err != nil {
+		panic(err)
 	}
-	return val
+
+	return must(base.mulRat(pct.Quo(pct, big.NewRat(100, 1)), mode))
+
 }
 
 //Round rounds a given currency to the given level of precision
-func (base *Currency) Round(decimalPlaces int, roundMode RoundingMode) *Currency {
+func (base *Currency) Round(precision int, roundMode RoundingMode) *Currency {
+
+	base.normalize()
 
-	floatValue := base.ToFloat()
+	if precision > maxDecimalPlaces {
+		precision = maxDecimalPlaces
+	}
 
-	denom := math.Pow10(decimalPlaces)
+	scaled := base.scaled()
 
-	switch roundMode {
-	case RoundDown:
-		floatValue = math.Floor(floatValue*denom) / denom
-	case RoundUp:
-		floatValue = math.Ceil(floatValue*denom) / denom
-	case RoundNearest:
-		floatValue = math.Round(floatValue*denom) / denom
+	//the rounded amount over 10^precision
+	var rounded *big.Int
+	if currentPlaces := decimalPlaces(base.DecimalDenominator); currentPlaces > precision {
+		rounded = roundQuo(scaled, bigPow10(currentPlaces-precision), roundMode)
+	} else {
+		rounded = new(big.Int).Mul(scaled, bigPow10(precision-currentPlaces))
+	}
+
+	//drop trailing zeros, but keep at least the currency's precision
+	places := precision
+	minPlaces := base.CurrencyDefinition().DecimalPlaces
+	ten := big.NewInt(10)
+	for places > minPlaces {
+		quo, rem := new(big.Int).QuoRem(rounded, ten, new(big.Int))
+		if rem.Sign() != 0 {
+			break
+		}
+		rounded = quo
+		places--
+	}
+	if places < minPlaces {
+		rounded.Mul(rounded, bigPow10(minPlaces-places))
+		places = minPlaces
 	}
 
-	return New(base.CurrencyCode, fmt.Sprintf("%f", floatValue))
+	return fromScaled(base.CurrencyCode, rounded, pow10(places))
 
 }
 
-func (base *Currency) toScaledInt() int {
-	absResult := base.Decimal + (intAbs(base.Integer) * base.DecimalDenominator)
-	if base.Negative {
-		absResult = -absResult
-	}
-	return absResult
+/*
+RoundToIncrement rounds the amount to a multiple of the increment, such as
+0.05 for cash payments in currencies without one cent coins. It panics if
+the currencies differ or the increment isn't positive.
+*/
+func (base *Currency) RoundToIncrement(increment *Currency, mode RoundingMode) *Currency {
+	return must(base.RoundToIncrementChecked(increment, mode))
 }
 
-func fromScaledInt(currencyCode string, scaledInt int, decimalDenominator int) *Currency {
+/*
+RoundToIncrementChecked rounds the amount to a multiple of the increment,
+returning an error if the currencies differ or the increment isn't positive.
+*/
+func (base *Currency) RoundToIncrementChecked(increment *Currency, mode RoundingMode) (*Currency, error) {
 
-	result := Currency{
-		CurrencyCode: currencyCode,
+	if increment == nil || increment.IsZero() || increment.Negative {
+		return nil, fmt.Errorf("%w: rounding increment must be positive", ErrInvalidAmount)
 	}
 
-	if decimalDenominator > 0 {
-		scaledAbs := intAbs(scaledInt)
-		result.Decimal = scaledInt % decimalDenominator
-		if result.Decimal < 0 {
-			result.Decimal = -result.Decimal
-		}
-		result.Integer = (scaledAbs - result.Decimal) / decimalDenominator
-		result.DecimalDenominator = decimalDenominator
-	} else {
-		result.Integer = scaledInt
+	if err := base.normalizeCurrency(increment); err != nil {
+		return nil, err
 	}
 
-	if scaledInt < 0 {
-		result.Negative = true
+	scaledBase, scaledIncrement, _ := toScaledInts(base, increment)
+
+	//the result is a whole number of increments, so it needs no more places than the increment
+	units := roundQuo(scaledBase, scaledIncrement, mode)
+
+	return fromScaledChecked(base.CurrencyCode, units.Mul(units, increment.scaled()), increment.DecimalDenominator)
+
+}
+
+/*
+RoundCash rounds the amount to the currency's cash increment, e.g. 0.05 for
+CHF and CAD, for cash payments at the point of sale. Currencies without a
+cash increment are rounded to their minor unit.
+*/
+func (base *Currency) RoundCash(mode RoundingMode) *Currency {
+
+	def := base.CurrencyDefinition()
+
+	increment := def.CashIncrement
+	if increment <= 0 {
+		increment = 1
 	}
 
-	return &result
+	return base.RoundToIncrement(fromScaled(def.Code, big.NewInt(int64(increment)), pow10(def.DecimalPlaces)), mode)
 
 }
 
 /*
-Mult multiplies itself by the operand and returns the result.
+Mult multiplies itself by the operand and returns the result, truncated to
+the receiver's precision. It panics if the currencies differ or the result
+overflows.
 */
 func (base *Currency) Mult(operand *Currency) *Currency {
+	return must(base.MultChecked(operand))
+}
 
-	base.normalizeCurrency(operand)
+/*
+MultRounded multiplies itself by the operand and returns the result, rounded
+to the receiver's precision with the given mode. It panics if the currencies
+differ or the result overflows.
+*/
+func (base *Currency) MultRounded(operand *Currency, mode RoundingMode) *Currency {
+	return must(base.mult(operand, mode))
+}
 
-	scaledBase := base.toScaledInt()
-	scaledOp := operand.toScaledInt()
+/*
+MultChecked multiplies itself by the operand and returns the result,
+truncated to the receiver's precision, or an error if the currencies differ
+or the result overflows.
+*/
+func (base *Currency) MultChecked(operand *Currency) (*Currency, error) {
+	return base.mult(operand, RoundTowardZero)
+}
 
-	scaledResult := scaledBase * scaledOp / base.DecimalDenominator
+func (base *Currency) mult(operand *Currency, mode RoundingMode) (*Currency, error) {
 
-	return fromScaledInt(base.CurrencyCode, scaledResult, base.DecimalDenominator)
+	if err := base.normalizeCurrency(operand); err != nil {
+		return nil, err
+	}
+
+	//zero amounts can be left without a denominator, and scale as whole units
+	denom := operand.DecimalDenominator
+	if denom == 0 {
+		denom = 1
+	}
+
+	scaledResult := new(big.Int).Mul(base.scaled(), operand.scaled())
+	scaledResult = roundQuo(scaledResult, big.NewInt(int64(denom)), mode)
+
+	return fromScaledChecked(base.CurrencyCode, scaledResult, base.DecimalDenominator)
 
 }
 
@@ -467,49 +598,90 @@ func (base *Currency) normalize() {
 
 	if base.DecimalDenominator == 0 {
 		def := base.CurrencyDefinition()
-		base.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+		base.DecimalDenominator = pow10(def.DecimalPlaces)
 	}
 
 }
 
 /*
-MultInt multiplies the receiver by an integer.
+MultInt multiplies the receiver by an integer. It panics if the result
+overflows.
 */
 func (base *Currency) MultInt(operand int) *Currency {
+	return must(base.MultIntChecked(operand))
+}
+
+/*
+MultIntChecked multiplies the receiver by an integer, returning an error if
+the currency is unsupported or the result overflows.
+*/
+func (base *Currency) MultIntChecked(operand int) (*Currency, error) {
 
 	if base.IsZero() {
-		return base
+		return base, nil
+	}
+
+	if _, err := base.definition(); err != nil {
+		return nil, err
 	}
 
 	base.normalize()
 
-	scaledBase := base.toScaledInt()
-	scaledResult := scaledBase * operand
+	scaledResult := new(big.Int).Mul(base.scaled(), big.NewInt(int64(operand)))
 
-	return fromScaledInt(base.CurrencyCode, scaledResult, base.DecimalDenominator)
+	return fromScaledChecked(base.CurrencyCode, scaledResult, base.DecimalDenominator)
 
 }
 
-// MultFloat multiplies the receiver by a floating point number.
+/*
+MultFloat multiplies the receiver by a floating point number, rounding to the
+receiver's precision. The operand is taken as the shortest decimal that
+represents it, so 0.1 is exactly one tenth. It panics if the operand is
+infinite or not a number, or the result overflows.
+*/
 func (base *Currency) MultFloat(operand float64, mode RoundingMode) *Currency {
+
+	ratio, err := floatRat(operand)
+	if err != nil {
+		panic(err)
+	}
+
+	return must(base.mulRat(ratio, mode))
+
+}
+
+// mulRat multiplies the receiver by an exact ratio, rounding the result to
+// the receiver's precision.
+func (base *Currency) mulRat(ratio *big.Rat, mode RoundingMode) (*Currency, error) {
+
 	if base.IsZero() {
-		return base
+		return base, nil
+	}
+
+	if _, err := base.definition(); err != nil {
+		return nil, err
 	}
 
 	base.normalize()
-	scaledBase := base.toScaledInt()
 
-	scaledResult := float64(scaledBase) * operand
+	scaledResult := new(big.Int).Mul(base.scaled(), ratio.Num())
 
-	var roundedResult int
-	switch mode {
-	case RoundUp:
-		roundedResult = int(math.Ceil(scaledResult))
-	case RoundNearest:
-		roundedResult = int(math.Round(scaledResult))
+	return fromScaledChecked(base.CurrencyCode, roundQuo(scaledResult, ratio.Denom(), mode), base.DecimalDenominator)
+
+}
+
+// floatRat converts a float to the exact value of its shortest decimal
+// representation.
+func floatRat(value float64) (*big.Rat, error) {
+
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, ErrOverflow
 	}
 
-	return fromScaledInt(base.CurrencyCode, roundedResult, base.DecimalDenominator)
+	ratio, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+
+	return ratio, nil
+
 }
 
 // ValidateCurrencySequential validates that the given basis points are sequential
@@ -534,96 +706,121 @@ func ValidateCurrencySequential(values ...*Currency) error {
 }
 
 /*
-LT executes a less than comparison.
+CompareChecked compares base to the operand, returning -1, 0 or +1 as base
+is less than, equal to or greater than the operand, or an error if the
+currencies differ.
 */
-func (base *Currency) LT(operand *Currency) bool {
-
-	base.normalizeCurrency(operand)
+func (base *Currency) CompareChecked(operand *Currency) (int, error) {
 
-	if base.Integer != operand.Integer {
-		return base.Integer < operand.Integer
+	if err := base.normalizeCurrency(operand); err != nil {
+		return 0, err
 	}
 
-	return base.Decimal < operand.Decimal
+	return compareScaled(base, operand), nil
 
 }
 
-/*
-LTE executes a less than comparison.
-*/
-func (base *Currency) LTE(operand *Currency) bool {
-
-	base.normalizeCurrency(operand)
+// compare is CompareChecked for the panicking comparisons.
+func (base *Currency) compare(operand *Currency) int {
 
-	if base.Equals(operand) {
-		return true
+	result, err := base.CompareChecked(operand)
+	if err != nil {
+		panic(err)
 	}
 
-	return base.LT(operand)
+	return result
 
 }
 
 /*
-GTE executes a less than comparison.
+LT executes a less than comparison.
 */
-func (base *Currency) GTE(operand *Currency) bool {
-
-	base.normalizeCurrency(operand)
-
-	if base.Equals(operand) {
-		return true
-	}
+func (base *Currency) LT(operand *Currency) bool {
+	return base.compare(operand) < 0
+}
 
-	return base.GT(operand)
+/*
+LTE executes a less than or equal comparison.
+*/
+func (base *Currency) LTE(operand *Currency) bool {
+	return base.compare(operand) <= 0
+}
 
+/*
+GTE executes a greater than or equal comparison.
+*/
+func (base *Currency) GTE(operand *Currency) bool {
+	return base.compare(operand) >= 0
 }
 
 /*
 GT executes a greater than comparison.
 */
 func (base *Currency) GT(operand *Currency) bool {
-
-	base.normalizeCurrency(operand)
-
-	if base.Integer != operand.Integer {
-		return base.Integer > operand.Integer
-	}
-
-	return base.Decimal > operand.Decimal
-
+	return base.compare(operand) > 0
 }
 
 /*
-DivideInt divides itself by the operand and returns the result.
+DivideInt divides itself by the operand and returns the result. It panics if
+the operand is zero.
+
+RoundUp, RoundDown and RoundNearest keep their original meaning here. For a
+positive amount they round the magnitude of the quotient up, down and to
+nearest with halves down; for a negative amount all three truncate. So
+-10.01 / 2 is -5.00 with any of them, and 0.05 / 2 is 0.03 with RoundUp and
+0.02 with the other two. The other modes round the signed quotient as they
+do elsewhere; use RoundCeiling, RoundFloor and RoundHalfUp for the rounding
+RoundUp, RoundDown and RoundNearest give in other operations.
 */
 func (base *Currency) DivideInt(operand int, mode RoundingMode) *Currency {
+	return must(base.DivideIntChecked(operand, mode))
+}
 
-	base.normalize()
+/*
+DivideIntChecked divides itself by the operand and returns the result, or an
+error if the currency is unsupported or the operand is zero.
+*/
+func (base *Currency) DivideIntChecked(operand int, mode RoundingMode) (*Currency, error) {
 
-	baseScaled := base.toScaledInt()
+	if _, err := base.definition(); err != nil {
+		return nil, err
+	}
 
-	absOperand := operand
+	if operand == 0 {
+		return nil, ErrDivideByZero
+	}
 
-	if operand < 0 {
-		absOperand = -operand
+	base.normalize()
+
+	switch mode {
+	case RoundUp, RoundDown, RoundNearest:
+	default:
+		resultScaled := roundQuo(base.scaled(), big.NewInt(int64(operand)), mode)
+		return fromScaledChecked(base.CurrencyCode, resultScaled, base.DecimalDenominator)
 	}
 
-	remainder := baseScaled % absOperand
+	baseScaled := base.scaled()
 
-	resultScaled := (baseScaled - remainder) / absOperand
+	absOperand := big.NewInt(int64(operand))
+	absOperand.Abs(absOperand)
+
+	resultScaled, remainder := new(big.Int).QuoRem(baseScaled, absOperand, new(big.Int))
 
 	switch mode {
 	case RoundUp:
-		if remainder > 0 {
-			resultScaled++
+		if remainder.Sign() > 0 {
+			resultScaled.Add(resultScaled, big.NewInt(1))
 		}
 	case RoundNearest:
-		if (absOperand - remainder) < remainder {
-			resultScaled++
+		if new(big.Int).Sub(absOperand, remainder).Cmp(remainder) < 0 {
+			resultScaled.Add(resultScaled, big.NewInt(1))
 		}
 	}
 
-	result := fromScaledInt(base.CurrencyCode, resultScaled, base.DecimalDenominator)
+	result, err := fromScaledChecked(base.CurrencyCode, resultScaled, base.DecimalDenominator)
+	if err != nil {
+		return nil, err
+	}
 
 	if base.Negative && (operand < 0) {
 		result.Negative = false
@@ -631,31 +828,48 @@ func (base *Currency) DivideInt(operand int, mode RoundingMode) *Currency {
 		result.Negative = true
 	}
 
-	return result
+	return result, nil
 
 }
 
 /*
-Divide divides itself by the operand and returns the result.
+Divide divides itself by the operand and returns the result, truncated to
+the currency's precision. It panics if the currencies differ or the operand
+is zero.
 */
 func (base *Currency) Divide(operand *Currency) *Currency {
+	return must(base.DivideChecked(operand))
+}
 
-	base.normalizeCurrency(operand)
-	decimalMultiplier := base.decimalMultiplier()
-
-	result := Currency{CurrencyCode: base.CurrencyCode}
+/*
+DivideRounded divides itself by the operand and returns the result, rounded
+to the currency's precision with the given mode. It panics if the currencies
+differ or the operand is zero.
+*/
+func (base *Currency) DivideRounded(operand *Currency, mode RoundingMode) *Currency {
+	return must(base.divide(operand, mode))
+}
 
-	baseFloat := float64(base.Integer) + float64(base.Decimal)/float64(decimalMultiplier)
-	opFloat := float64(operand.Integer) + float64(operand.Decimal)/float64(decimalMultiplier)
+/*
+DivideChecked divides itself by the operand and returns the result,
+truncated to the currency's precision, or an error if the currencies differ
+or the operand is zero.
+*/
+func (base *Currency) DivideChecked(operand *Currency) (*Currency, error) {
+	return base.divide(operand, RoundTowardZero)
+}
 
-	resultFloat := baseFloat / opFloat
+func (base *Currency) divide(operand *Currency, mode RoundingMode) (*Currency, error) {
 
-	resultInt := int(resultFloat * float64(decimalMultiplier))
+	if operand.IsZero() {
+		return nil, ErrDivideByZero
+	}
 
-	result.Integer = resultInt / decimalMultiplier
-	result.Decimal = resultInt % decimalMultiplier
+	if err := base.normalizeCurrency(operand); err != nil {
+		return nil, err
+	}
 
-	return &result
+	return FromRat(base.CurrencyCode, new(big.Rat).Quo(base.Rat(), operand.Rat()), mode)
 
 }
 
@@ -783,7 +997,7 @@ func (base *Currency) FormatCurrency() string {
 	rawDecimal := strconv.Itoa(base.Decimal)
 
 	if base.DecimalDenominator != 0 {
-		decPlaces := int(math.Log10(float64(base.DecimalDenominator)))
+		decPlaces := decimalPlaces(base.DecimalDenominator)
 
 		if len(rawDecimal) < decPlaces {
 			b.WriteString(strings.Repeat("0", decPlaces-len(rawDecimal)))
@@ -832,99 +1046,161 @@ func isZeroString(value string) bool {
 
 /*
 ParseCurrency parses a string encoded currency for the given currency code.
+It panics if the currency is unsupported or the amount is invalid; use
+TryParse for input that hasn't been validated.
 */
 func ParseCurrency(currencyCode string, value string) *Currency {
+	return must(TryParse(currencyCode, value))
+}
 
-	if isZeroString(value) {
-		return Zero()
-	}
+/*
+TryParse parses a string encoded currency for the given currency code. The
+amount may include the currency's symbol or code and thousands separators.
+It returns ErrUnsupportedCurrency for unknown currencies, ErrInvalidAmount
+for anything else that isn't a number and ErrOverflow for amounts that don't
+fit in a Currency. Empty and zero amounts parse as Zero.
+*/
+func TryParse(currencyCode string, value string) (*Currency, error) {
 
-	def, ok := CurrencyDefinitionMap[currencyCode]
-	if !ok {
-		panic(currencyCode + " is not a supported currency")
+	if isZeroString(value) {
+		return Zero(), nil
 	}
 
-	if strings.Contains(value, def.DecimalSeparator) {
-		value = strings.TrimRight(value, "0")
+	def, err := LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
 	}
 
 	currency := Currency{CurrencyCode: currencyCode}
 
-	if strings.HasPrefix(value, "-") {
+	digits := stripSymbols(value, currencyCode, def)
+
+	if strings.HasPrefix(digits, "-") {
 		currency.Negative = true
-		value = value[1:]
+		digits = digits[1:]
+	}
+
+	tokens := strings.Split(digits, def.DecimalSeparator)
+	if len(tokens) > 2 || tokens[0] == "" && (len(tokens) == 1 || tokens[1] == "") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
 	}
 
-	tokens := strings.Split(value, def.DecimalSeparator)
+	for _, token := range tokens {
+		if strings.Trim(token, "0123456789") != "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+	}
 
-	for i := range tokens {
-		tokens[i] = stripSymbols(tokens[i])
+	if currency.Integer, err = parseDigits(tokens[0]); err != nil {
+		return nil, err
 	}
 
-	if len(tokens) > 0 {
-		currency.Integer, _ = strconv.Atoi(tokens[0])
-		if len(tokens) > 1 {
-			padded := padDecimal(tokens[1], def)
-			currency.Decimal, _ = strconv.Atoi(padded)
-			if len(tokens[1]) > def.DecimalPlaces {
-				currency.DecimalDenominator = int(math.Pow10(len(tokens[1])))
-			} else {
-				currency.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
-			}
+	if len(tokens) > 1 {
+		decimal := strings.TrimRight(tokens[1], "0")
+		places := def.DecimalPlaces
+		if len(decimal) > places {
+			places = len(decimal)
 		}
-	} else {
-		currency.DecimalDenominator = int(math.Pow10(def.DecimalPlaces))
+		if places > maxDecimalPlaces {
+			return nil, ErrOverflow
+		}
+		if currency.Decimal, err = parseDigits(padDecimal(decimal, def)); err != nil {
+			return nil, err
+		}
+		currency.DecimalDenominator = pow10(places)
 	}
 
 	if currency.IsZero() {
 		currency.Negative = false
 	}
 
-	return &currency
+	return &currency, nil
 
 }
 
-func stripSymbols(numeric string) string {
-	filter := func(r rune) rune {
-		if r < '0' || r > '9' {
-			// Non-numeric ascii character
-			return -1
-		}
+// parseDigits parses a string of digits, which is zero if empty, returning
+// ErrOverflow if it doesn't fit in an int.
+func parseDigits(digits string) (int, error) {
+
+	if digits == "" {
+		return 0, nil
+	}
+
+	result, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, ErrOverflow
+	}
+
+	return result, nil
+
+}
 
-		return r
+// stripSymbols removes the currency's symbol and code, thousands separators
+// and spaces, leaving the sign, digits and decimal separator. A minus sign
+// after the symbol is moved to the front.
+func stripSymbols(value string, currencyCode string, def CurrencyDefinition) string {
+
+	replacements := []string{currencyCode, "", " ", "", "\u00a0", ""}
+	if def.CurrencySymbol != "" {
+		replacements = append(replacements, def.CurrencySymbol, "")
+	}
+	if def.ThousandsSeparator != "" && def.ThousandsSeparator != def.DecimalSeparator {
+		replacements = append(replacements, def.ThousandsSeparator, "")
 	}
 
-	return strings.Map(filter, numeric)
+	return strings.NewReplacer(replacements...).Replace(strings.TrimSpace(value))
+
 }
 
-// ParseNumericCode parses a numeric encoded amount to currency.
+/*
+ParseNumericCode parses an amount encoded as digits in the currency's smallest
+unit, as reported by payment terminals. The currency can be given by its
+alphabetic or ISO 4217 numeric code. It panics on unsupported currencies and
+invalid amounts; use TryParseNumericCode to get an error instead.
+*/
 func ParseNumericCode(currencyCode string, valueCode string) *Currency {
-	def := CurrencyDefinitionMap[currencyCode]
-	var err error
+	return must(TryParseNumericCode(currencyCode, valueCode))
+}
+
+/*
+TryParseNumericCode parses an amount encoded as digits in the currency's
+smallest unit, returning ErrUnsupportedCurrency, ErrInvalidAmount or
+ErrOverflow for bad input.
+*/
+func TryParseNumericCode(currencyCode string, valueCode string) (*Currency, error) {
+
+	def, err := resolveCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if valueCode == "" || strings.Trim(valueCode, "0123456789") != "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, valueCode)
+	}
 
 	var integer, decimal int
 	if len(valueCode) > def.DecimalPlaces {
-		decimal, err = strconv.Atoi(valueCode[len(valueCode)-def.DecimalPlaces : len(valueCode)])
-		if err != nil {
-			panic(err)
+		split := len(valueCode) - def.DecimalPlaces
+		if def.DecimalPlaces > 0 {
+			if decimal, err = parseDigits(valueCode[split:]); err != nil {
+				return nil, err
+			}
 		}
 
-		integer, err = strconv.Atoi(valueCode[:len(valueCode)-def.DecimalPlaces])
-		if err != nil {
-			panic(err)
-		}
-	} else {
-		decimal, err = strconv.Atoi(valueCode)
-		if err != nil {
-			panic(err)
+		if integer, err = parseDigits(valueCode[:split]); err != nil {
+			return nil, err
 		}
+	} else if decimal, err = parseDigits(valueCode); err != nil {
+		return nil, err
 	}
 
 	return &Currency{
-		CurrencyCode: CurrencyCodeUSD,
-		Integer:      integer,
-		Decimal:      decimal,
-	}
+		CurrencyCode:       def.Code,
+		Integer:            integer,
+		Decimal:            decimal,
+		DecimalDenominator: pow10(def.DecimalPlaces),
+	}, nil
+
 }
 
 // padDecimal zero pads a decimal to the correct length for the currency.
@@ -941,35 +1217,23 @@ func padDecimal(raw string, def CurrencyDefinition) string {
 	return b.String()
 }
 
-//UnmarshalJSON implements the json.Unmarshaler interface.
+// UnmarshalJSON implements the json.Unmarshaler interface. It reads every
+// JSONFormat, and plain numbers, which are read as USD.
 func (base *Currency) UnmarshalJSON(b []byte) error {
-	if b[0] == '"' {
-		var raw string
-		if err := json.Unmarshal(b, &raw); err != nil {
-			return err
-		}
 
-		*base = *ParseCurrency(CurrencyCodeUSD, raw)
-		return nil
-	}
-
-	var raw float64
-	if err := json.Unmarshal(b, &raw); err != nil {
+	parsed, err := unmarshalCurrencyJSON(b)
+	if err != nil {
 		return err
 	}
 
-	*base = FromFloat64(CurrencyCodeUSD, raw)
+	*base = *parsed
 
 	return nil
+
 }
 
-// MarshalJSON implements the json.Marshaler interface.
+// MarshalJSON implements the json.Marshaler interface, writing the format set
+// with SetJSONFormat.
 func (base Currency) MarshalJSON() ([]byte, error) {
-	var b bytes.Buffer
-
-	b.WriteString("\"")
-	b.WriteString(base.String())
-	b.WriteString("\"")
-
-	return b.Bytes(), nil
+	return marshalCurrencyJSON(base)
 }
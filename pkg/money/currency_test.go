@@ -2,7 +2,10 @@ package money
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -403,10 +406,10 @@ func TestMultiplication(t *testing.T) {
 			expect:  &Currency{Integer: 0, Decimal: 0, CurrencyCode: CurrencyCodeUSD},
 		},
 		{
-			name:        "ZeroZero",
-			base:        Currency{},
-			operand:     Currency{},
-			shouldPanic: true,
+			name:    "ZeroZero",
+			base:    Currency{},
+			operand: Currency{},
+			expect:  &Currency{},
 		},
 		{
 			name:    "SmallNegativeBase",
@@ -777,6 +780,9 @@ func TestUnmarshalJSON(t *testing.T) {
 }
 
 func TestMarshalJSON(t *testing.T) {
+
+	defer SetJSONFormat(SetJSONFormat(JSONLegacy))
+
 	tests := []struct {
 		name   string
 		input  marshallerTestStruct
@@ -962,3 +968,296 @@ func BenchmarkParseCurrency(b *testing.B) {
 		ParseCurrency(CurrencyCodeUSD, "123,456.78")
 	}
 }
+
+func TestLargeAmounts(t *testing.T) {
+
+	assert := assert.New(t)
+
+	//scaled to satoshis, this product needs more than 64 bits before dividing
+	btc := New(CurrencyCodeBTC, "123456.12345678")
+	result := btc.Mult(New(CurrencyCodeBTC, "1000.00000001"))
+	assert.Equal("123,456,123.45801456", result.FormatCurrency())
+
+	sum := New(CurrencyCodeBTC, "92233720368.54775807").Add(New(CurrencyCodeBTC, "0.00000001"))
+	assert.Equal("92,233,720,368.54775808", sum.FormatCurrency())
+
+	max := New(CurrencyCodeUSD, "9223372036854775807.00")
+	assert.Equal("9,223,372,036,854,775,806.00", max.Add(One(CurrencyCodeUSD).Negate()).FormatCurrency())
+
+	assert.PanicsWithValue(ErrOverflow, func() { max.Add(max) })
+	assert.PanicsWithValue(ErrOverflow, func() { max.MultInt(10) })
+	assert.PanicsWithValue(ErrOverflow, func() { ParseCurrency(CurrencyCodeUSD, "92233720368547758070") })
+
+	fractional := Currency{Integer: 1, Decimal: 5, DecimalDenominator: 1000000000000000000, CurrencyCode: CurrencyCodeUSD}
+	assert.Equal("1.000000000000000005", fractional.MultInt(1).FormatCurrency())
+	assert.Equal("2.000000000000000010", fractional.Add(&fractional).FormatCurrency())
+
+}
+
+func TestComparisons(t *testing.T) {
+
+	assert := assert.New(t)
+
+	negative := New(CurrencyCodeUSD, "-5.00")
+	positive := New(CurrencyCodeUSD, "1.00")
+	precise := &Currency{Integer: 1, Decimal: 0, DecimalDenominator: 1000, CurrencyCode: CurrencyCodeUSD}
+
+	assert.True(negative.LT(positive))
+	assert.True(positive.GT(negative))
+	assert.True(positive.Equals(precise))
+	assert.False(positive.Equals(positive.Negate()))
+	assert.True(New(CurrencyCodeUSD, "1.005").GT(positive))
+
+}
+
+func TestTryParse(t *testing.T) {
+
+	assert := assert.New(t)
+
+	result, err := TryParse(CurrencyCodeUSD, "$-1,234.50")
+	assert.NoError(err)
+	assert.Equal("-1,234.50", result.FormatCurrency())
+
+	result, err = TryParse(CurrencyCodeCAD, "CAD 12")
+	assert.NoError(err)
+	assert.Equal("12.00", result.FormatCurrency())
+
+	result, err = TryParse(CurrencyCodeUSD, "")
+	assert.NoError(err)
+	assert.True(result.IsZero())
+
+	for _, input := range []string{"12abc", "1.2.3", "-", "$", "1e5", "--5"} {
+		_, err = TryParse(CurrencyCodeUSD, input)
+		assert.True(errors.Is(err, ErrInvalidAmount), input)
+	}
+
+	_, err = TryParse("XYZ", "1.00")
+	assert.True(errors.Is(err, ErrUnsupportedCurrency))
+
+	_, err = TryParse(CurrencyCodeUSD, "92233720368547758070")
+	assert.Equal(ErrOverflow, err)
+
+	_, err = TryParse(CurrencyCodeUSD, "0.1234567890123456789")
+	assert.Equal(ErrOverflow, err)
+
+	assert.Panics(func() { ParseCurrency(CurrencyCodeUSD, "12abc") })
+
+	result, err = TryParseNumericCode("978", "1050")
+	assert.NoError(err)
+	assert.Equal(CurrencyCodeEUR, result.CurrencyCode)
+	assert.Equal(10, result.Integer)
+	assert.Equal(50, result.Decimal)
+
+	_, err = TryParseNumericCode(CurrencyCodeUSD, "")
+	assert.True(errors.Is(err, ErrInvalidAmount))
+
+	_, err = TryParseNumericCode(CurrencyCodeUSD, "-100")
+	assert.True(errors.Is(err, ErrInvalidAmount))
+
+	_, err = TryParseNumericCode("999", "100")
+	assert.True(errors.Is(err, ErrUnsupportedCurrency))
+
+}
+
+func TestCheckedOperations(t *testing.T) {
+
+	assert := assert.New(t)
+
+	dollars := New(CurrencyCodeUSD, "10.00")
+	euros := New(CurrencyCodeEUR, "5,00")
+
+	_, err := dollars.AddChecked(euros)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = dollars.SubtractChecked(euros)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = dollars.MultChecked(euros)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = dollars.CompareChecked(euros)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = dollars.DivideChecked(euros)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	//zero amounts match any currency
+	sum, err := dollars.AddChecked(Zero())
+	assert.NoError(err)
+	assert.Equal("10.00", sum.FormatCurrency())
+
+	sum, err = dollars.AddChecked(New(CurrencyCodeUSD, "2.50"))
+	assert.NoError(err)
+	assert.Equal("12.50", sum.FormatCurrency())
+
+	cmp, err := dollars.CompareChecked(New(CurrencyCodeUSD, "10.001"))
+	assert.NoError(err)
+	assert.Equal(-1, cmp)
+
+	_, err = dollars.DivideIntChecked(0, RoundNearest)
+	assert.Equal(ErrDivideByZero, err)
+
+	_, err = dollars.DivideChecked(Zero())
+	assert.Equal(ErrDivideByZero, err)
+
+	_, err = (&Currency{CurrencyCode: "XYZ", Integer: 1}).MultIntChecked(2)
+	assert.True(errors.Is(err, ErrUnsupportedCurrency))
+
+	max := New(CurrencyCodeUSD, "9223372036854775807.00")
+	_, err = max.AddChecked(max)
+	assert.Equal(ErrOverflow, err)
+
+	_, err = max.MultIntChecked(2)
+	assert.Equal(ErrOverflow, err)
+
+	assert.Panics(func() { dollars.Add(euros) })
+	assert.Panics(func() { dollars.LT(euros) })
+	assert.PanicsWithValue(ErrDivideByZero, func() { dollars.DivideInt(0, RoundDown) })
+
+}
+
+func TestRoundingModes(t *testing.T) {
+
+	//expected results for 2.5, 1.5, 1.25, -1.25, -1.5 and -2.5 rounded to whole units
+	tests := []struct {
+		name   string
+		mode   RoundingMode
+		expect []string
+	}{
+		{"RoundUp", RoundUp, []string{"3", "2", "2", "-1", "-1", "-2"}},
+		{"RoundDown", RoundDown, []string{"2", "1", "1", "-2", "-2", "-3"}},
+		{"RoundNearest", RoundNearest, []string{"3", "2", "1", "-1", "-2", "-3"}},
+		{"RoundHalfEven", RoundHalfEven, []string{"2", "2", "1", "-1", "-2", "-2"}},
+		{"RoundHalfDown", RoundHalfDown, []string{"2", "1", "1", "-1", "-1", "-2"}},
+		{"RoundTowardZero", RoundTowardZero, []string{"2", "1", "1", "-1", "-1", "-2"}},
+		{"RoundAwayFromZero", RoundAwayFromZero, []string{"3", "2", "2", "-2", "-2", "-3"}},
+	}
+
+	values := []int64{250, 150, 125, -125, -150, -250}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, value := range values {
+				assert.Equal(t, test.expect[i], roundQuo(big.NewInt(value), big.NewInt(100), test.mode).String(), "%v", value)
+			}
+		})
+	}
+
+	for _, pair := range [][2]RoundingMode{{RoundUp, RoundCeiling}, {RoundDown, RoundFloor}, {RoundNearest, RoundHalfUp}} {
+		assert.NotEqual(t, pair[0], pair[1])
+		for _, value := range values {
+			assert.Equal(t, roundQuo(big.NewInt(value), big.NewInt(100), pair[0]), roundQuo(big.NewInt(value), big.NewInt(100), pair[1]))
+		}
+	}
+
+	assert.Equal(t, "2.12", ParseCurrency(CurrencyCodeUSD, "2.125").Round(2, RoundHalfEven).FormatCurrency())
+	assert.Equal(t, "2.14", ParseCurrency(CurrencyCodeUSD, "2.135").Round(2, RoundHalfEven).FormatCurrency())
+	assert.Equal(t, "-2.13", ParseCurrency(CurrencyCodeUSD, "-2.125").Round(2, RoundAwayFromZero).FormatCurrency())
+
+}
+
+func TestRoundedOperations(t *testing.T) {
+
+	assert := assert.New(t)
+
+	//one third of a cent
+	price := ParseCurrency(CurrencyCodeUSD, "1.00")
+	assert.Equal("0.33", price.DivideInt(3, RoundNearest).FormatCurrency())
+	assert.Equal("0.34", price.DivideInt(3, RoundUp).FormatCurrency())
+	assert.Equal("-0.33", price.Negate().DivideInt(3, RoundCeiling).FormatCurrency())
+	assert.Equal("-0.34", price.Negate().DivideInt(3, RoundFloor).FormatCurrency())
+	assert.Equal("-0.34", price.DivideInt(-3, RoundAwayFromZero).FormatCurrency())
+
+	//DivideInt keeps the original behavior of the original modes
+	assert.Equal("-0.33", price.Negate().DivideInt(3, RoundUp).FormatCurrency())
+	assert.Equal("-5.00", ParseCurrency(CurrencyCodeUSD, "-10.01").DivideInt(2, RoundDown).FormatCurrency())
+	assert.Equal("-5.01", ParseCurrency(CurrencyCodeUSD, "-10.01").DivideInt(2, RoundFloor).FormatCurrency())
+	assert.Equal("-0.33", price.DivideInt(-3, RoundNearest).FormatCurrency())
+	assert.Equal("0.02", ParseCurrency(CurrencyCodeUSD, "0.05").DivideInt(2, RoundNearest).FormatCurrency())
+	assert.Equal("0.02", ParseCurrency(CurrencyCodeUSD, "0.05").DivideInt(2, RoundDown).FormatCurrency())
+	assert.Equal("0.03", ParseCurrency(CurrencyCodeUSD, "0.05").DivideInt(2, RoundUp).FormatCurrency())
+	assert.Equal("-5.00", ParseCurrency(CurrencyCodeUSD, "-10.01").DivideInt(2, RoundUp).FormatCurrency())
+	assert.Equal("-5.00", ParseCurrency(CurrencyCodeUSD, "-10.01").DivideInt(2, RoundNearest).FormatCurrency())
+	assert.Equal("0.03", ParseCurrency(CurrencyCodeUSD, "0.05").DivideInt(2, RoundHalfUp).FormatCurrency())
+
+	//0.125 ties
+	eighth := ParseCurrency(CurrencyCodeUSD, "0.25")
+	assert.Equal("0.12", eighth.DivideInt(2, RoundHalfEven).FormatCurrency())
+	assert.Equal("0.12", eighth.DivideInt(2, RoundHalfDown).FormatCurrency())
+	assert.Equal("0.13", eighth.DivideInt(2, RoundHalfUp).FormatCurrency())
+	assert.Equal("-0.13", eighth.Negate().DivideInt(2, RoundHalfUp).FormatCurrency())
+
+	half := ParseCurrency(CurrencyCodeUSD, "0.50")
+	assert.Equal("0.12", ParseCurrency(CurrencyCodeUSD, "0.25").Mult(half).FormatCurrency())
+	assert.Equal("0.13", ParseCurrency(CurrencyCodeUSD, "0.25").MultRounded(half, RoundNearest).FormatCurrency())
+	assert.Equal("-0.12", ParseCurrency(CurrencyCodeUSD, "-0.25").MultRounded(half, RoundHalfEven).FormatCurrency())
+
+	//zero operands without a denominator
+	product, err := Zero().MultChecked(Zero())
+	assert.NoError(err)
+	assert.True(product.IsZero())
+	product, err = ParseCurrency(CurrencyCodeUSD, "2.50").MultChecked(&Currency{CurrencyCode: CurrencyCodeUSD})
+	assert.NoError(err)
+	assert.Equal("0.00", product.FormatCurrency())
+	assert.True(half.MultRounded(Zero(), RoundNearest).IsZero())
+	assert.True(Zero().Mult(half).IsZero())
+
+	assert.Equal("0.33", price.Divide(ParseCurrency(CurrencyCodeUSD, "3.00")).FormatCurrency())
+	assert.Equal("0.67", ParseCurrency(CurrencyCodeUSD, "2.00").DivideRounded(ParseCurrency(CurrencyCodeUSD, "3.00"), RoundNearest).FormatCurrency())
+
+	//7.5% of 0.10 is 0.0075
+	dime := ParseCurrency(CurrencyCodeUSD, "0.10")
+	assert.Equal("0.01", dime.MultBPS(NewBasisPoints(750)).FormatCurrency())
+	assert.Equal("0.00", dime.MultBPSRounded(NewBasisPoints(750), RoundDown).FormatCurrency())
+	assert.Equal("0.01", dime.MultBPSRounded(NewBasisPoints(750), RoundHalfEven).FormatCurrency())
+	assert.Equal("0.00", dime.Pct(7.5).FormatCurrency())
+	assert.Equal("0.01", dime.PctRounded(7.5, RoundNearest).FormatCurrency())
+	assert.Equal("-0.01", dime.Negate().PctRounded(7.5, RoundDown).FormatCurrency())
+
+	//0.1 is exact, so ties round as written
+	assert.Equal("0.02", ParseCurrency(CurrencyCodeUSD, "0.15").MultFloat(0.1, RoundHalfEven).FormatCurrency())
+	assert.Equal("0.02", ParseCurrency(CurrencyCodeUSD, "0.25").MultFloat(0.1, RoundHalfEven).FormatCurrency())
+	assert.Equal("0.03", ParseCurrency(CurrencyCodeUSD, "0.25").MultFloat(0.1, RoundNearest).FormatCurrency())
+
+	assert.Panics(func() { dime.MultFloat(math.Inf(1), RoundNearest) })
+
+}
+
+func TestCashRounding(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal(5, ParseCurrency("CHF", "1").CurrencyDefinition().CashIncrement)
+
+	tests := []struct {
+		code   string
+		value  string
+		mode   RoundingMode
+		expect string
+	}{
+		{"CHF", "1.02", RoundNearest, "1.00"},
+		{"CHF", "1.03", RoundNearest, "1.05"},
+		{"CHF", "1.025", RoundNearest, "1.05"},
+		{"CHF", "1.025", RoundHalfEven, "1.00"},
+		{"CAD", "-1.03", RoundNearest, "-1.05"},
+		{"CAD", "-1.03", RoundUp, "-1.00"},
+		{"CAD", "1.01", RoundUp, "1.05"},
+		{"SEK", "12.50", RoundNearest, "13.00"},
+		{"USD", "1.03", RoundNearest, "1.03"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(test.expect, ParseCurrency(test.code, test.value).RoundCash(test.mode).FormatCurrency(), "%v %v", test.code, test.value)
+	}
+
+	quarter := ParseCurrency(CurrencyCodeUSD, "0.25")
+	assert.Equal("1.25", ParseCurrency(CurrencyCodeUSD, "1.37").RoundToIncrement(quarter, RoundNearest).FormatCurrency())
+	assert.Equal("1.50", ParseCurrency(CurrencyCodeUSD, "1.38").RoundToIncrement(quarter, RoundNearest).FormatCurrency())
+
+	_, err := quarter.RoundToIncrementChecked(Zero(), RoundNearest)
+	assert.True(errors.Is(err, ErrInvalidAmount))
+
+	_, err = quarter.RoundToIncrementChecked(ParseCurrency(CurrencyCodeCAD, "0.05"), RoundNearest)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+}
@@ -0,0 +1,311 @@
+package money
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// JSONFormat selects how currencies are written to JSON.
+type JSONFormat int32
+
+// JSONFormat constants.
+const (
+	JSONObject JSONFormat = iota //{"amount":"1234.50","currency":"EUR"}
+	JSONCode                     //"EUR 1234.50"
+	JSONLegacy                   //"1,234.50", formatted without the currency, which is read back as USD
+)
+
+var jsonFormat = int32(JSONObject)
+
+/*
+SetJSONFormat sets the format MarshalJSON writes and returns the previous
+one. UnmarshalJSON reads every format regardless, so clients can be moved
+over one at a time. Set it at startup.
+*/
+func SetJSONFormat(format JSONFormat) JSONFormat {
+	return JSONFormat(atomic.SwapInt32(&jsonFormat, int32(format)))
+}
+
+// SQLFormat selects how Value writes currencies to the database.
+type SQLFormat int32
+
+// SQLFormat constants.
+const (
+	SQLCanonical SQLFormat = iota //1234.50, with a period whatever the currency
+	SQLLegacy                     //1234,50 for EUR, with the currency's own decimal separator
+)
+
+var sqlFormat = int32(SQLCanonical)
+
+/*
+SetSQLFormat sets the format Value writes and returns the previous one. Scan
+reads both regardless, so existing rows stay readable. Set it at startup.
+*/
+func SetSQLFormat(format SQLFormat) SQLFormat {
+	return SQLFormat(atomic.SwapInt32(&sqlFormat, int32(format)))
+}
+
+// currencyJSON is the JSONObject wire format.
+type currencyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+/*
+DecimalString returns the amount as a plain decimal with a period separator
+and no grouping, e.g. -1234.50, showing every decimal place the amount
+carries. It's the amount used by the JSON and text formats.
+*/
+func (base Currency) DecimalString() string {
+
+	if base.CurrencyCode == "" && base.IsZero() {
+		return "0"
+	}
+
+	places := decimalPlaces(base.DecimalDenominator)
+	if base.DecimalDenominator == 0 {
+		places = base.CurrencyDefinition().DecimalPlaces
+	}
+
+	var b strings.Builder
+
+	if base.Negative && !base.IsZero() {
+		b.WriteByte('-')
+	}
+
+	b.WriteString(strconv.Itoa(base.Integer))
+
+	if places > 0 {
+		decimal := strconv.Itoa(base.Decimal)
+		b.WriteByte('.')
+		if len(decimal) < places {
+			b.WriteString(strings.Repeat("0", places-len(decimal)))
+		}
+		b.WriteString(decimal)
+	}
+
+	return b.String()
+
+}
+
+/*
+CodeString returns the currency code followed by the plain decimal amount,
+e.g. EUR 1234.50. Amounts without a currency are written as 0.
+*/
+func (base Currency) CodeString() string {
+
+	if base.CurrencyCode == "" {
+		return base.DecimalString()
+	}
+
+	return base.CurrencyCode + " " + base.DecimalString()
+
+}
+
+/*
+ParseCodeString parses the CodeString format, e.g. EUR 1234.50. A plain
+decimal without a code is parsed in the given default currency.
+*/
+func ParseCodeString(value string, defaultCurrencyCode string) (*Currency, error) {
+
+	value = strings.TrimSpace(value)
+	code := defaultCurrencyCode
+
+	if idx := strings.IndexByte(value, ' '); idx > 0 {
+		code = value[:idx]
+		value = strings.TrimSpace(value[idx+1:])
+	}
+
+	return parseCanonical(code, value)
+
+}
+
+// parseCanonical parses a plain decimal with a period separator, as written
+// by DecimalString and SQL, regardless of the currency's own separator.
+func parseCanonical(currencyCode string, value string) (*Currency, error) {
+
+	def, err := LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	if negative {
+		value = value[1:]
+	}
+
+	result, err := parseDecimal(def, value, ".", negative)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, value)
+	}
+
+	return result, nil
+
+}
+
+// marshalCurrencyJSON writes the currency in the configured JSON format.
+func marshalCurrencyJSON(base Currency) ([]byte, error) {
+
+	switch JSONFormat(atomic.LoadInt32(&jsonFormat)) {
+	case JSONCode:
+		return json.Marshal(base.CodeString())
+	case JSONLegacy:
+		return json.Marshal(base.String())
+	}
+
+	return json.Marshal(currencyJSON{Amount: base.DecimalString(), Currency: base.CurrencyCode})
+
+}
+
+// unmarshalCurrencyJSON reads any of the JSON formats. Strings and numbers
+// without a currency code are read as USD.
+func unmarshalCurrencyJSON(b []byte) (*Currency, error) {
+
+	switch {
+	case len(b) > 0 && b[0] == '{':
+		var raw currencyJSON
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		if raw.Currency == "" {
+			if isZeroString(raw.Amount) {
+				return Zero(), nil
+			}
+			return nil, fmt.Errorf("%w: amount %q has no currency", ErrUnsupportedCurrency, raw.Amount)
+		}
+		return parseCanonical(raw.Currency, raw.Amount)
+
+	case len(b) > 0 && b[0] == '"':
+		var raw string
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		if hasCodePrefix(raw) {
+			return ParseCodeString(raw, CurrencyCodeUSD)
+		}
+		return TryParse(CurrencyCodeUSD, raw)
+	}
+
+	var raw float64
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	result := FromFloat64(CurrencyCodeUSD, raw)
+
+	return &result, nil
+
+}
+
+// hasCodePrefix reports whether a string starts with an upper case currency
+// code followed by a space, as in the JSONCode format.
+func hasCodePrefix(value string) bool {
+
+	idx := strings.IndexByte(value, ' ')
+	if idx < 3 {
+		return false
+	}
+
+	for _, r := range value[:idx] {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+/*
+scanCurrency parses a database value. Besides plain decimals, which keep the
+receiver's currency code or default to USD, it reads the CodeString format
+and Postgres composite values such as (1234.50,EUR). Plain decimals may use
+the currency's own decimal separator, as SQLLegacy writes them. Those never
+group digits, so a comma is read as a decimal separator too while the code
+isn't known yet, which lets the companion code column be scanned second.
+*/
+func scanCurrency(value string, currencyCode string) (*Currency, error) {
+
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		fields := strings.Split(value[1:len(value)-1], ",")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+		return parseCanonical(strings.Trim(fields[1], `" `), strings.Trim(fields[0], `" `))
+	}
+
+	legacySeparator := ","
+	if currencyCode == "" {
+		currencyCode = CurrencyCodeUSD
+	} else if def, err := LookupCurrency(currencyCode); err == nil {
+		legacySeparator = def.DecimalSeparator
+	}
+
+	if hasCodePrefix(value) {
+		return ParseCodeString(value, currencyCode)
+	}
+
+	if legacySeparator != "." && legacySeparator != "" && strings.Contains(value, legacySeparator) && !strings.Contains(value, ".") {
+		value = strings.Replace(value, legacySeparator, ".", 1)
+	}
+
+	return parseCanonical(currencyCode, value)
+
+}
+
+/*
+CurrencyCodeScanner returns a scanner for a companion column holding the
+currency's code, for amounts stored as a plain decimal next to their code:
+
+	var price money.Currency
+	rows.Scan(&price, price.CurrencyCodeScanner())
+
+The columns can be scanned in either order.
+*/
+func (base *Currency) CurrencyCodeScanner() sql.Scanner {
+	return currencyCodeScanner{base}
+}
+
+type currencyCodeScanner struct {
+	target *Currency
+}
+
+// Scan implements sql.Scanner.
+func (scanner currencyCodeScanner) Scan(src interface{}) error {
+
+	var code string
+
+	switch value := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		code = string(value)
+	case string:
+		code = value
+	default:
+		return fmt.Errorf("money: cannot scan %T into a currency code", src)
+	}
+
+	def, err := LookupCurrency(strings.TrimSpace(code))
+	if err != nil {
+		return err
+	}
+
+	target := scanner.target
+	target.CurrencyCode = def.Code
+
+	//keep at least the currency's precision if the amount was scanned first
+	if minDenom := pow10(def.DecimalPlaces); target.DecimalDenominator != 0 && target.DecimalDenominator < minDenom {
+		target.Decimal = target.Decimal * (minDenom / target.DecimalDenominator)
+		target.DecimalDenominator = minDenom
+	}
+
+	return nil
+
+}
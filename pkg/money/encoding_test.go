@@ -0,0 +1,166 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFormats(t *testing.T) {
+
+	assert := assert.New(t)
+
+	euros := marshallerTestStruct{Money: Currency{Integer: 1234, Decimal: 5, DecimalDenominator: 100, CurrencyCode: CurrencyCodeEUR}}
+	bitcoin := marshallerTestStruct{Money: Currency{Integer: 0, Decimal: 12345, DecimalDenominator: 100000000, Negative: true, CurrencyCode: CurrencyCodeBTC}}
+
+	tests := []struct {
+		format JSONFormat
+		input  marshallerTestStruct
+		expect string
+		lossy  bool
+	}{
+		{JSONObject, euros, `{"money":{"amount":"1234.05","currency":"EUR"}}`, false},
+		{JSONObject, bitcoin, `{"money":{"amount":"-0.00012345","currency":"BTC"}}`, false},
+		{JSONObject, marshallerTestStruct{}, `{"money":{"amount":"0","currency":""}}`, false},
+		{JSONCode, euros, `{"money":"EUR 1234.05"}`, false},
+		{JSONCode, bitcoin, `{"money":"BTC -0.00012345"}`, false},
+		{JSONLegacy, euros, `{"money":"1.234,05"}`, true},
+	}
+
+	for _, test := range tests {
+		previous := SetJSONFormat(test.format)
+		result, err := json.Marshal(test.input)
+		SetJSONFormat(previous)
+
+		assert.NoError(err)
+		assert.Equal(test.expect, string(result))
+
+		var decoded marshallerTestStruct
+		assert.NoError(json.Unmarshal(result, &decoded))
+		if !test.lossy {
+			assert.Equal(test.input.Money.CurrencyCode, decoded.Money.CurrencyCode, test.expect)
+			assert.True(test.input.Money.Equals(&decoded.Money), test.expect)
+		}
+	}
+
+	var decoded marshallerTestStruct
+	assert.True(errors.Is(json.Unmarshal([]byte(`{"money":{"amount":"1.00","currency":"XYZ"}}`), &decoded), ErrUnsupportedCurrency))
+	assert.True(errors.Is(json.Unmarshal([]byte(`{"money":{"amount":"1.00"}}`), &decoded), ErrUnsupportedCurrency))
+	assert.True(errors.Is(json.Unmarshal([]byte(`{"money":{"amount":"1,00","currency":"EUR"}}`), &decoded), ErrInvalidAmount))
+	assert.True(errors.Is(json.Unmarshal([]byte(`{"money":"EUR 1.2.3"}`), &decoded), ErrInvalidAmount))
+
+}
+
+func TestScan(t *testing.T) {
+
+	assert := assert.New(t)
+
+	var amount Currency
+	assert.NoError(amount.Scan([]byte("1234.50")))
+	assert.Equal(CurrencyCodeUSD, amount.CurrencyCode)
+	assert.Equal("1,234.50", amount.FormatCurrency())
+
+	//a plain decimal keeps the receiver's currency
+	amount = Currency{CurrencyCode: CurrencyCodeEUR}
+	assert.NoError(amount.Scan("1234.50"))
+	assert.Equal(CurrencyCodeEUR, amount.CurrencyCode)
+	assert.Equal(1234, amount.Integer)
+	assert.Equal(50, amount.Decimal)
+
+	assert.NoError(amount.Scan("JPY 1500"))
+	assert.Equal("JPY", amount.CurrencyCode)
+	assert.Equal(1500, amount.Integer)
+
+	assert.NoError(amount.Scan(`(-0.5,"BTC")`))
+	assert.Equal(CurrencyCodeBTC, amount.CurrencyCode)
+	assert.Equal("-0.50000000", amount.DecimalString())
+
+	assert.NoError(amount.Scan(nil))
+	assert.True(amount.IsZero())
+
+	assert.True(errors.Is(amount.Scan("abc"), ErrInvalidAmount))
+	assert.Error(amount.Scan(12.5))
+
+	//companion code column, scanned after the amount
+	var price Currency
+	assert.NoError(price.Scan([]byte("0.5")))
+	assert.NoError(price.CurrencyCodeScanner().Scan([]byte("BTC")))
+	assert.Equal(CurrencyCodeBTC, price.CurrencyCode)
+	assert.Equal("0.50000000", price.DecimalString())
+
+	//and before it
+	price = Currency{}
+	assert.NoError(price.CurrencyCodeScanner().Scan("EUR"))
+	assert.NoError(price.Scan([]byte("-12.30")))
+	assert.Equal(CurrencyCodeEUR, price.CurrencyCode)
+	assert.Equal("-12.30", price.DecimalString())
+
+	assert.True(errors.Is(price.CurrencyCodeScanner().Scan("XYZ"), ErrUnsupportedCurrency))
+
+	value, err := price.Value()
+	assert.NoError(err)
+	assert.Equal("-12.30", value)
+
+	//values written before the canonical format used the currency's separator
+	legacy := Currency{CurrencyCode: CurrencyCodeEUR}
+	assert.NoError(legacy.Scan([]byte("1234,50")))
+	assert.Equal(CurrencyCodeEUR, legacy.CurrencyCode)
+	assert.Equal("1234.50", legacy.DecimalString())
+
+	legacy = Currency{}
+	assert.NoError(legacy.Scan([]byte("-1234,5")))
+	assert.NoError(legacy.CurrencyCodeScanner().Scan("EUR"))
+	assert.Equal("EUR -1234.50", legacy.CodeString())
+
+	legacy = Currency{CurrencyCode: CurrencyCodeUSD}
+	assert.True(errors.Is(legacy.Scan("1234,50"), ErrInvalidAmount))
+
+}
+
+func TestSQLFormat(t *testing.T) {
+
+	assert := assert.New(t)
+
+	euros := Currency{CurrencyCode: CurrencyCodeEUR, Integer: 1234, Decimal: 5, DecimalDenominator: 100}
+
+	value, err := euros.Value()
+	assert.NoError(err)
+	assert.Equal("1234.05", value)
+
+	defer SetSQLFormat(SetSQLFormat(SQLLegacy))
+
+	value, err = euros.Value()
+	assert.NoError(err)
+	assert.Equal("1234,05", value)
+
+	scanned := Currency{CurrencyCode: CurrencyCodeEUR}
+	assert.NoError(scanned.Scan(value))
+	assert.True(euros.Equals(&scanned))
+
+	value, err = Currency{CurrencyCode: CurrencyCodeUSD, Integer: 7, DecimalDenominator: 100}.Value()
+	assert.NoError(err)
+	assert.Equal("7.00", value)
+
+}
+
+func TestDecimalAndCodeStrings(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.Equal("0", Zero().DecimalString())
+	assert.Equal("12.00", Currency{Integer: 12, CurrencyCode: CurrencyCodeUSD}.DecimalString())
+	assert.Equal("1234567.891", Currency{Integer: 1234567, Decimal: 891, DecimalDenominator: 1000, CurrencyCode: CurrencyCodeUSD}.DecimalString())
+	assert.Equal("JPY 500", Currency{Integer: 500, CurrencyCode: "JPY"}.CodeString())
+
+	parsed, err := ParseCodeString("CAD -7.25", CurrencyCodeUSD)
+	assert.NoError(err)
+	assert.Equal(CurrencyCodeCAD, parsed.CurrencyCode)
+	assert.Equal("-7.25", parsed.DecimalString())
+
+	parsed, err = ParseCodeString("7.25", CurrencyCodeGBP)
+	assert.NoError(err)
+	assert.Equal(CurrencyCodeGBP, parsed.CurrencyCode)
+
+}
@@ -0,0 +1,353 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidFeeSchedule is returned for fee schedules that can't be applied.
+var ErrInvalidFeeSchedule = errors.New("invalid fee schedule")
+
+// FeeBasis selects what a fee schedule's breakpoints measure.
+type FeeBasis string
+
+// FeeBasis constants.
+const (
+	FeeBasisAmount   FeeBasis = "amount"   //tiers start at transaction amounts
+	FeeBasisQuantity FeeBasis = "quantity" //tiers start at unit counts, e.g. tickets in an order
+)
+
+// FeeMethod selects how a fee schedule's tiers combine.
+type FeeMethod string
+
+// FeeMethod constants.
+const (
+	FeeTiered    FeeMethod = "tiered"    //the whole transaction is charged at the tier it reaches
+	FeeGraduated FeeMethod = "graduated" //each portion is charged at its own tier, like tax brackets
+)
+
+/*
+FeeTier is one band of a fee schedule, starting at From for amount schedules
+or FromQuantity for quantity schedules. The first tier must start at zero.
+The fee is Fixed plus Rate of the amount, raised to Min and limited to Cap;
+any of them may be left out. Quantity schedules charge Fixed per unit.
+Graduated schedules only charge a later tier once the measure is past its
+start. Amounts are always written to JSON as objects with their currency,
+whatever SetJSONFormat is set to.
+*/
+type FeeTier struct {
+	From         *Currency    `json:"from,omitempty"`
+	FromQuantity int          `json:"fromQuantity,omitempty"`
+	Fixed        *Currency    `json:"fixed,omitempty"`
+	Rate         *BasisPoints `json:"rate,omitempty"`
+	Min          *Currency    `json:"min,omitempty"`
+	Cap          *Currency    `json:"cap,omitempty"`
+}
+
+/*
+FeeSchedule computes service and processing fees from tiers with ascending
+breakpoints. Fees are computed exactly and rounded once, to the currency's
+precision, with Mode. Schedules are stored as JSON, in a text column when
+saved to the database.
+*/
+type FeeSchedule struct {
+	CurrencyCode string       `json:"currency"`
+	Basis        FeeBasis     `json:"basis"`
+	Method       FeeMethod    `json:"method"`
+	Mode         RoundingMode `json:"rounding"`
+	Tiers        []FeeTier    `json:"tiers"`
+}
+
+/*
+Validate checks the schedule's tiers are in its currency and start at zero
+with strictly ascending breakpoints, and that each tier's minimum doesn't
+exceed its cap.
+*/
+func (schedule FeeSchedule) Validate() error {
+
+	if _, err := LookupCurrency(schedule.CurrencyCode); err != nil {
+		return err
+	}
+
+	if schedule.Basis != FeeBasisAmount && schedule.Basis != FeeBasisQuantity {
+		return fmt.Errorf("%w: unknown basis %q", ErrInvalidFeeSchedule, schedule.Basis)
+	}
+
+	if schedule.Method != FeeTiered && schedule.Method != FeeGraduated {
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidFeeSchedule, schedule.Method)
+	}
+
+	if len(schedule.Tiers) == 0 {
+		return fmt.Errorf("%w: no tiers", ErrInvalidFeeSchedule)
+	}
+
+	breakpoints := make([]*Currency, len(schedule.Tiers))
+
+	for i, tier := range schedule.Tiers {
+		for _, value := range []*Currency{tier.From, tier.Fixed, tier.Min, tier.Cap} {
+			if err := schedule.checkAmount(i, value); err != nil {
+				return err
+			}
+		}
+		if tier.Rate != nil && tier.Rate.Numerator != 0 && (tier.Rate.Numerator < 0 || tier.Rate.Denominator <= 0) {
+			return fmt.Errorf("%w: tier %v rate must be non-negative", ErrInvalidFeeSchedule, i)
+		}
+		if tier.Min != nil && tier.Cap != nil {
+			if err := ValidateCurrencySequential(tier.Min, tier.Cap); err != nil {
+				return fmt.Errorf("%w: tier %v minimum exceeds its cap", ErrInvalidFeeSchedule, i)
+			}
+		}
+		breakpoints[i] = tier.From
+		if breakpoints[i] == nil {
+			breakpoints[i] = Zero()
+		}
+	}
+
+	if schedule.Basis == FeeBasisQuantity {
+		for i, tier := range schedule.Tiers {
+			if i == 0 && tier.FromQuantity != 0 || i > 0 && tier.FromQuantity <= schedule.Tiers[i-1].FromQuantity {
+				return fmt.Errorf("%w: tier %v quantity out of sequence", ErrInvalidFeeSchedule, i)
+			}
+		}
+		return nil
+	}
+
+	if !breakpoints[0].IsZero() {
+		return fmt.Errorf("%w: the first tier must start at zero", ErrInvalidFeeSchedule)
+	}
+
+	if err := ValidateCurrencySequential(breakpoints...); err != nil {
+		return fmt.Errorf("%w: tiers %v", ErrInvalidFeeSchedule, err)
+	}
+
+	for i := 1; i < len(breakpoints); i++ {
+		if breakpoints[i].IsZero() || breakpoints[i].Equals(breakpoints[i-1]) {
+			return fmt.Errorf("%w: tier %v starts at the same amount as tier %v", ErrInvalidFeeSchedule, i, i-1)
+		}
+	}
+
+	return nil
+
+}
+
+// checkAmount checks a tier amount is non-negative and in the schedule's
+// currency. Nil and zero amounts are allowed.
+func (schedule FeeSchedule) checkAmount(tier int, value *Currency) error {
+
+	if value == nil || value.IsZero() {
+		return nil
+	}
+
+	if value.CurrencyCode != schedule.CurrencyCode {
+		return fmt.Errorf("%w: tier %v has a %v amount in a %v schedule", ErrCurrencyMismatch, tier, value.CurrencyCode, schedule.CurrencyCode)
+	}
+
+	if value.Negative {
+		return fmt.Errorf("%w: tier %v has a negative amount", ErrInvalidFeeSchedule, tier)
+	}
+
+	return nil
+
+}
+
+/*
+Fee returns the fee for a transaction of the given amount and number of
+units. Amount schedules pick tiers by the amount and ignore the quantity;
+quantity schedules pick tiers by the quantity and charge rates on the
+amount.
+*/
+func (schedule FeeSchedule) Fee(amount *Currency, quantity int) (*Currency, error) {
+
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if amount == nil || amount.Negative && !amount.IsZero() || quantity < 0 {
+		return nil, fmt.Errorf("%w: fees need a non-negative amount and quantity", ErrInvalidAmount)
+	}
+
+	if !amount.IsZero() && amount.CurrencyCode != schedule.CurrencyCode {
+		return nil, fmt.Errorf("%w: %v amount in a %v schedule", ErrCurrencyMismatch, amount.CurrencyCode, schedule.CurrencyCode)
+	}
+
+	value := new(big.Rat)
+	if !amount.IsZero() {
+		normalized := *amount
+		normalized.normalize()
+		value = normalized.Rat()
+	}
+
+	measure := value
+	if schedule.Basis == FeeBasisQuantity {
+		measure = big.NewRat(int64(quantity), 1)
+	}
+
+	total := new(big.Rat)
+
+	for i, tier := range schedule.Tiers {
+		lower := schedule.breakpoint(i)
+		if measure.Cmp(lower) < 0 {
+			break
+		}
+
+		next := i + 1
+		if schedule.Method == FeeTiered {
+			if next < len(schedule.Tiers) && measure.Cmp(schedule.breakpoint(next)) >= 0 {
+				continue
+			}
+			total.Add(total, tier.fee(value, big.NewRat(int64(quantity), 1), schedule.Basis))
+			break
+		}
+
+		//the part of the measure inside this tier
+		portion := new(big.Rat).Set(measure)
+		if next < len(schedule.Tiers) && portion.Cmp(schedule.breakpoint(next)) > 0 {
+			portion.Set(schedule.breakpoint(next))
+		}
+		portion.Sub(portion, lower)
+
+		//a measure exactly on a breakpoint hasn't reached the tier's minimum yet
+		if i > 0 && portion.Sign() == 0 {
+			break
+		}
+
+		if schedule.Basis == FeeBasisAmount {
+			total.Add(total, tier.fee(portion, big.NewRat(1, 1), schedule.Basis))
+			continue
+		}
+
+		//quantity tiers charge rates on their units' share of the amount
+		share := new(big.Rat)
+		if quantity > 0 {
+			share.Mul(value, portion)
+			share.Quo(share, big.NewRat(int64(quantity), 1))
+		}
+		total.Add(total, tier.fee(share, portion, schedule.Basis))
+	}
+
+	return FromRat(schedule.CurrencyCode, total, schedule.Mode)
+
+}
+
+// breakpoint returns where a tier starts as an exact number.
+func (schedule FeeSchedule) breakpoint(i int) *big.Rat {
+
+	tier := schedule.Tiers[i]
+
+	if schedule.Basis == FeeBasisQuantity {
+		return big.NewRat(int64(tier.FromQuantity), 1)
+	}
+
+	return exactAmount(tier.From)
+
+}
+
+// fee returns the exact fee for an amount and number of units in the tier.
+func (tier FeeTier) fee(amount *big.Rat, units *big.Rat, basis FeeBasis) *big.Rat {
+
+	result := new(big.Rat).Mul(amount, tier.Rate.Rat())
+
+	fixed := exactAmount(tier.Fixed)
+	if basis == FeeBasisQuantity {
+		fixed.Mul(fixed, units)
+	}
+	result.Add(result, fixed)
+
+	if tier.Min != nil && result.Cmp(exactAmount(tier.Min)) < 0 {
+		result = exactAmount(tier.Min)
+	}
+
+	if tier.Cap != nil && result.Cmp(exactAmount(tier.Cap)) > 0 {
+		result = exactAmount(tier.Cap)
+	}
+
+	return result
+
+}
+
+// feeTierJSON is the FeeTier wire format, fixing the amounts to the
+// JSONObject format so stored schedules keep their currency.
+type feeTierJSON struct {
+	From         *currencyJSON `json:"from,omitempty"`
+	FromQuantity int           `json:"fromQuantity,omitempty"`
+	Fixed        *currencyJSON `json:"fixed,omitempty"`
+	Rate         *BasisPoints  `json:"rate,omitempty"`
+	Min          *currencyJSON `json:"min,omitempty"`
+	Cap          *currencyJSON `json:"cap,omitempty"`
+}
+
+// objectJSON returns an amount in the JSONObject format, or nil.
+func objectJSON(value *Currency) *currencyJSON {
+
+	if value == nil {
+		return nil
+	}
+
+	return &currencyJSON{Amount: value.DecimalString(), Currency: value.CurrencyCode}
+
+}
+
+// MarshalJSON implements the json.Marshaler interface. Currency.UnmarshalJSON
+// reads the amounts back, so FeeTier needs no UnmarshalJSON.
+func (tier FeeTier) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(feeTierJSON{
+		From:         objectJSON(tier.From),
+		FromQuantity: tier.FromQuantity,
+		Fixed:        objectJSON(tier.Fixed),
+		Rate:         tier.Rate,
+		Min:          objectJSON(tier.Min),
+		Cap:          objectJSON(tier.Cap),
+	})
+
+}
+
+// exactAmount returns an amount as an exact number, treating nil as zero.
+func exactAmount(value *Currency) *big.Rat {
+
+	if value == nil || value.IsZero() {
+		return new(big.Rat)
+	}
+
+	normalized := *value
+	normalized.normalize()
+
+	return normalized.Rat()
+
+}
+
+/*
+Value implements the valuer interface in order to support sql serialization.
+*/
+func (schedule FeeSchedule) Value() (driver.Value, error) {
+
+	b, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+
+}
+
+/*
+Scan implements the scan interface in order to support sql serialization.
+*/
+func (schedule *FeeSchedule) Scan(src interface{}) error {
+
+	switch value := src.(type) {
+	case nil:
+		*schedule = FeeSchedule{}
+		return nil
+	case []byte:
+		return json.Unmarshal(value, schedule)
+	case string:
+		return json.Unmarshal([]byte(value), schedule)
+	}
+
+	return fmt.Errorf("money: cannot scan %T into a fee schedule", src)
+
+}
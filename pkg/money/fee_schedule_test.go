@@ -0,0 +1,234 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func processingSchedule() FeeSchedule {
+
+	return FeeSchedule{
+		CurrencyCode: CurrencyCodeUSD,
+		Basis:        FeeBasisAmount,
+		Method:       FeeGraduated,
+		Mode:         RoundNearest,
+		Tiers: []FeeTier{
+			{Fixed: ParseCurrency(CurrencyCodeUSD, "0.30"), Rate: NewBasisPoints(290)},
+			{From: ParseCurrency(CurrencyCodeUSD, "1000.00"), Rate: NewBasisPoints(250), Cap: ParseCurrency(CurrencyCodeUSD, "50.00")},
+		},
+	}
+
+}
+
+type feeTest struct {
+	name     string
+	schedule FeeSchedule
+	amount   string
+	quantity int
+	expect   string
+}
+
+func TestFeeSchedule(t *testing.T) {
+
+	assert := assert.New(t)
+
+	tests := []feeTest{
+		{"GraduatedFirstTier", processingSchedule(), "10.00", 1, "0.59"},
+		{"GraduatedTwoTiers", processingSchedule(), "1500.00", 1, "41.80"},
+		{"GraduatedCapped", processingSchedule(), "5000.00", 1, "79.30"},
+		{"GraduatedZero", processingSchedule(), "0.00", 1, "0.30"},
+	}
+
+	service := FeeSchedule{
+		CurrencyCode: CurrencyCodeUSD,
+		Basis:        FeeBasisQuantity,
+		Method:       FeeTiered,
+		Mode:         RoundUp,
+		Tiers: []FeeTier{
+			{Fixed: ParseCurrency(CurrencyCodeUSD, "1.50"), Rate: NewBasisPoints(300)},
+			{FromQuantity: 10, Fixed: ParseCurrency(CurrencyCodeUSD, "1.00"), Rate: NewBasisPoints(250)},
+		},
+	}
+
+	tests = append(tests, []feeTest{
+		{"TieredBelowBreak", service, "100.00", 4, "9.00"},
+		{"TieredAtBreak", service, "250.00", 10, "16.25"},
+		{"TieredRoundsUp", service, "33.33", 1, "2.50"},
+	}...)
+
+	perUnit := FeeSchedule{
+		CurrencyCode: CurrencyCodeUSD,
+		Basis:        FeeBasisQuantity,
+		Method:       FeeGraduated,
+		Mode:         RoundNearest,
+		Tiers: []FeeTier{
+			{Fixed: ParseCurrency(CurrencyCodeUSD, "2.00")},
+			{FromQuantity: 5, Fixed: ParseCurrency(CurrencyCodeUSD, "1.00"), Rate: NewBasisPoints(100)},
+		},
+	}
+
+	minimum := FeeSchedule{
+		CurrencyCode: CurrencyCodeUSD,
+		Basis:        FeeBasisAmount,
+		Method:       FeeTiered,
+		Mode:         RoundNearest,
+		Tiers:        []FeeTier{{Rate: NewBasisPoints(100), Min: ParseCurrency(CurrencyCodeUSD, "0.50")}},
+	}
+
+	//a later tier's minimum applies once the amount passes its breakpoint
+	breakpoint := FeeSchedule{
+		CurrencyCode: CurrencyCodeUSD,
+		Basis:        FeeBasisAmount,
+		Method:       FeeGraduated,
+		Mode:         RoundNearest,
+		Tiers: []FeeTier{
+			{Rate: NewBasisPoints(300)},
+			{From: ParseCurrency(CurrencyCodeUSD, "1000.00"), Rate: NewBasisPoints(200), Min: ParseCurrency(CurrencyCodeUSD, "5.00")},
+		},
+	}
+
+	tests = append(tests, []feeTest{
+		{"BelowBreakpoint", breakpoint, "999.99", 1, "30.00"},
+		{"OnBreakpoint", breakpoint, "1000.00", 1, "30.00"},
+		{"PastBreakpoint", breakpoint, "1000.01", 1, "35.00"},
+		{"OnQuantityBreakpoint", perUnit, "50.00", 5, "10.00"},
+	}...)
+
+	tests = append(tests, []feeTest{
+		//five units at 2.00, two at 1.00 plus 1% of their 20.00 share
+		{"GraduatedQuantity", perUnit, "70.00", 7, "12.20"},
+		{"Minimum", minimum, "10.00", 1, "0.50"},
+		{"AboveMinimum", minimum, "80.00", 1, "0.80"},
+	}...)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fee, err := test.schedule.Fee(ParseCurrency(CurrencyCodeUSD, test.amount), test.quantity)
+			assert.NoError(err)
+			assert.Equal(test.expect, fee.FormatCurrency())
+			assert.Equal(CurrencyCodeUSD, fee.CurrencyCode)
+		})
+	}
+
+	_, err := processingSchedule().Fee(ParseCurrency(CurrencyCodeCAD, "10.00"), 1)
+	assert.True(errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = processingSchedule().Fee(ParseCurrency(CurrencyCodeUSD, "-10.00"), 1)
+	assert.True(errors.Is(err, ErrInvalidAmount))
+
+}
+
+func TestFeeScheduleValidation(t *testing.T) {
+
+	assert := assert.New(t)
+
+	assert.NoError(processingSchedule().Validate())
+
+	invalid := map[string]func(*FeeSchedule){
+		"NoTiers":          func(s *FeeSchedule) { s.Tiers = nil },
+		"UnknownBasis":     func(s *FeeSchedule) { s.Basis = "weight" },
+		"UnknownMethod":    func(s *FeeSchedule) { s.Method = "flat" },
+		"FirstTierNotZero": func(s *FeeSchedule) { s.Tiers[0].From = ParseCurrency(CurrencyCodeUSD, "1.00") },
+		"OutOfSequence": func(s *FeeSchedule) {
+			s.Tiers = append(s.Tiers, FeeTier{From: ParseCurrency(CurrencyCodeUSD, "500.00")})
+		},
+		"DuplicateBreakpoint": func(s *FeeSchedule) {
+			s.Tiers = append(s.Tiers, FeeTier{From: ParseCurrency(CurrencyCodeUSD, "1000.00")})
+		},
+		"MinAboveCap":   func(s *FeeSchedule) { s.Tiers[1].Min = ParseCurrency(CurrencyCodeUSD, "60.00") },
+		"NegativeRate":  func(s *FeeSchedule) { s.Tiers[0].Rate = NewBasisPoints(-1) },
+		"NoDenominator": func(s *FeeSchedule) { s.Tiers[0].Rate = &BasisPoints{Numerator: 5} },
+		"NegativeFixed": func(s *FeeSchedule) { s.Tiers[0].Fixed = ParseCurrency(CurrencyCodeUSD, "-0.30") },
+		"QuantityOutOfSequence": func(s *FeeSchedule) {
+			s.Basis = FeeBasisQuantity
+			s.Tiers[0].FromQuantity = 5
+			s.Tiers[1].FromQuantity = 2
+		},
+	}
+
+	for name, mutate := range invalid {
+		schedule := processingSchedule()
+		mutate(&schedule)
+		err := schedule.Validate()
+		assert.True(errors.Is(err, ErrInvalidFeeSchedule), "%v: %v", name, err)
+	}
+
+	//a zero rate, as parsed from an empty string, charges only the fixed fee
+	schedule := processingSchedule()
+	schedule.Tiers[0].Rate = &BasisPoints{}
+	assert.NoError(schedule.Validate())
+	schedule.Tiers[0].Rate, _ = ParseBasisPoints("")
+	fee, err := schedule.Fee(ParseCurrency(CurrencyCodeUSD, "10.00"), 1)
+	assert.NoError(err)
+	assert.Equal("0.30", fee.FormatCurrency())
+
+	schedule = processingSchedule()
+	schedule.Tiers[1].Cap = ParseCurrency(CurrencyCodeCAD, "50.00")
+	assert.True(errors.Is(schedule.Validate(), ErrCurrencyMismatch))
+
+	schedule.CurrencyCode = "XYZ"
+	assert.True(errors.Is(schedule.Validate(), ErrUnsupportedCurrency))
+
+}
+
+func TestFeeScheduleSerialization(t *testing.T) {
+
+	assert := assert.New(t)
+
+	schedule := processingSchedule()
+	schedule.Tiers[0].Rate = NewFractionalBasisPoints(1, 3)
+
+	b, err := json.Marshal(schedule)
+	assert.NoError(err)
+
+	var decoded FeeSchedule
+	assert.NoError(json.Unmarshal(b, &decoded))
+	assert.Equal(FeeGraduated, decoded.Method)
+	assert.Equal(RoundNearest, decoded.Mode)
+	assert.Equal(NewFractionalBasisPoints(1, 3), decoded.Tiers[0].Rate)
+	assert.Nil(decoded.Tiers[0].From)
+	assert.Equal("1,000.00", decoded.Tiers[1].From.FormatCurrency())
+
+	value, err := schedule.Value()
+	assert.NoError(err)
+
+	var scanned FeeSchedule
+	assert.NoError(scanned.Scan([]byte(value.(string))))
+	assert.NoError(scanned.Validate())
+
+	expected, err := schedule.Fee(ParseCurrency(CurrencyCodeUSD, "1500.00"), 1)
+	assert.NoError(err)
+	actual, err := scanned.Fee(ParseCurrency(CurrencyCodeUSD, "1500.00"), 1)
+	assert.NoError(err)
+	assert.True(expected.Equals(actual))
+
+	assert.Error(scanned.Scan(42))
+
+	assert.NoError(scanned.Scan(nil))
+	assert.Empty(scanned.Tiers)
+
+	//stored schedules keep their currency under the legacy format
+	defer SetJSONFormat(SetJSONFormat(JSONLegacy))
+
+	euros := FeeSchedule{
+		CurrencyCode: CurrencyCodeEUR,
+		Basis:        FeeBasisAmount,
+		Method:       FeeGraduated,
+		Tiers: []FeeTier{
+			{Rate: NewBasisPoints(300)},
+			{From: ParseCurrency(CurrencyCodeEUR, "1000,00"), Rate: NewBasisPoints(200), Min: ParseCurrency(CurrencyCodeEUR, "5,00")},
+		},
+	}
+
+	value, err = euros.Value()
+	assert.NoError(err)
+	assert.Contains(value, `"from":{"amount":"1000.00","currency":"EUR"}`)
+
+	assert.NoError(scanned.Scan(value))
+	assert.NoError(scanned.Validate())
+	assert.Equal(CurrencyCodeEUR, scanned.Tiers[1].Min.CurrencyCode)
+
+}
@@ -0,0 +1,252 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+)
+
+// maxDecimalPlaces is the largest number of decimal places whose denominator
+// fits in an int64.
+const maxDecimalPlaces = 18
+
+// ErrOverflow reports results that don't fit in a Currency. The unchecked
+// functions panic with it.
+var ErrOverflow = errors.New("currency amount out of range")
+
+// Currency amounts are stored as an integer part and a decimal part over a
+// power of ten denominator. Arithmetic converts both operands to scaled
+// integers, meaning the signed amount times the denominator. Scaled values can
+// exceed 64 bits long before the result does, for example when multiplying
+// two BTC amounts with eight decimal places, so they're held in big.Ints and
+// only checked for range when converted back into a Currency.
+
+// pow10 returns ten to the given power, panicking with ErrOverflow if it
+// doesn't fit in an int64.
+func pow10(places int) int {
+
+	if places < 0 || places > maxDecimalPlaces {
+		panic(ErrOverflow)
+	}
+
+	result := 1
+	for i := 0; i < places; i++ {
+		result *= 10
+	}
+
+	return result
+
+}
+
+// bigPow10 returns ten to the given power.
+func bigPow10(places int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+}
+
+// decimalPlaces returns the number of decimal places a power of ten
+// denominator represents.
+func decimalPlaces(denominator int) int {
+
+	places := 0
+	for denominator >= 10 {
+		denominator /= 10
+		places++
+	}
+
+	return places
+
+}
+
+// mulInt multiplies two ints, panicking with ErrOverflow on overflow.
+func mulInt(a int, b int) int {
+	return bigToInt(new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(b))))
+}
+
+// scaled returns the signed amount times the denominator. An unset
+// denominator is treated as one.
+func (base *Currency) scaled() *big.Int {
+
+	denom := base.DecimalDenominator
+	if denom == 0 {
+		denom = 1
+	}
+
+	integer := big.NewInt(int64(base.Integer))
+	integer.Abs(integer)
+
+	result := integer.Mul(integer, big.NewInt(int64(denom)))
+	result.Add(result, big.NewInt(int64(base.Decimal)))
+
+	if base.Negative {
+		result.Neg(result)
+	}
+
+	return result
+
+}
+
+/*
+Rat returns the amount as an exact rational number, for calculations that
+divide, such as backing tax out of an inclusive price, before rounding the
+result with FromRat.
+*/
+func (base *Currency) Rat() *big.Rat {
+
+	denom := base.DecimalDenominator
+	if denom == 0 {
+		denom = 1
+	}
+
+	return new(big.Rat).SetFrac(base.scaled(), big.NewInt(int64(denom)))
+
+}
+
+/*
+FromRat converts an exact rational amount into a currency, rounded to the
+currency's decimal places with the given rounding mode.
+*/
+func FromRat(currencyCode string, value *big.Rat, mode RoundingMode) (*Currency, error) {
+
+	def, err := LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	denom := pow10(def.DecimalPlaces)
+	numerator := new(big.Int).Mul(value.Num(), big.NewInt(int64(denom)))
+
+	return fromScaledChecked(def.Code, roundQuo(numerator, value.Denom(), mode), denom)
+
+}
+
+// rescale multiplies a scaled value so that it's expressed over a larger
+// denominator.
+func rescale(value *big.Int, fromDenom int, toDenom int) *big.Int {
+
+	if toDenom <= fromDenom || fromDenom == 0 {
+		return value
+	}
+
+	return new(big.Int).Mul(value, big.NewInt(int64(toDenom/fromDenom)))
+
+}
+
+// fromScaled converts a scaled value back into a Currency, panicking with
+// ErrOverflow if the integer part doesn't fit in an int.
+func fromScaled(currencyCode string, scaled *big.Int, decimalDenominator int) *Currency {
+	return must(fromScaledChecked(currencyCode, scaled, decimalDenominator))
+}
+
+// fromScaledChecked converts a scaled value back into a Currency, returning
+// ErrOverflow if the integer part doesn't fit in an int.
+func fromScaledChecked(currencyCode string, scaled *big.Int, decimalDenominator int) (*Currency, error) {
+
+	result := Currency{
+		CurrencyCode: currencyCode,
+	}
+
+	var err error
+	if decimalDenominator > 0 {
+		integer, decimal := new(big.Int).QuoRem(new(big.Int).Abs(scaled), big.NewInt(int64(decimalDenominator)), new(big.Int))
+		if result.Integer, err = toInt(integer); err != nil {
+			return nil, err
+		}
+		result.Decimal = int(decimal.Int64())
+		result.DecimalDenominator = decimalDenominator
+	} else if result.Integer, err = toInt(scaled); err != nil {
+		return nil, err
+	}
+
+	if scaled.Sign() < 0 {
+		result.Negative = true
+	}
+
+	return &result, nil
+
+}
+
+// bigToInt converts a big.Int to an int, panicking with ErrOverflow if it
+// doesn't fit.
+func bigToInt(value *big.Int) int {
+
+	result, err := toInt(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+
+}
+
+// toInt converts a big.Int to an int, returning ErrOverflow if it doesn't fit.
+func toInt(value *big.Int) (int, error) {
+
+	if !value.IsInt64() || int64(int(value.Int64())) != value.Int64() {
+		return 0, ErrOverflow
+	}
+
+	return int(value.Int64()), nil
+
+}
+
+// must unwraps the result of a checked operation for the panicking API.
+func must(result *Currency, err error) *Currency {
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+
+}
+
+// compareScaled compares two amounts numerically, taking signs and
+// denominators into account.
+func compareScaled(op1, op2 *Currency) int {
+
+	r1, r2, _ := toScaledInts(op1, op2)
+
+	return r1.Cmp(r2)
+
+}
+
+// roundQuo divides a scaled value, rounding the quotient with the given mode.
+// The rounding modes are symmetric about zero except RoundUp and RoundDown,
+// which round toward positive and negative infinity like RoundCeiling and
+// RoundFloor.
+func roundQuo(value *big.Int, divisor *big.Int, mode RoundingMode) *big.Int {
+
+	quo, rem := new(big.Int).QuoRem(value, divisor, new(big.Int))
+
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	//the sign of the exact quotient
+	sign := value.Sign() * divisor.Sign()
+	away := false
+
+	switch mode {
+	case RoundUp, RoundCeiling:
+		away = sign > 0
+	case RoundDown, RoundFloor:
+		away = sign < 0
+	case RoundAwayFromZero:
+		away = true
+	case RoundNearest, RoundHalfUp, RoundHalfEven, RoundHalfDown:
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		switch twice.Cmp(new(big.Int).Abs(divisor)) {
+		case 1:
+			away = true
+		case 0:
+			away = mode == RoundNearest || mode == RoundHalfUp || (mode == RoundHalfEven && quo.Bit(0) == 1)
+		}
+	}
+
+	if away {
+		quo.Add(quo, big.NewInt(int64(sign)))
+	}
+
+	return quo
+
+}
@@ -0,0 +1,109 @@
+//go:build ignore
+// +build ignore
+
+/*
+gen_iso4217 regenerates iso4217.go from the ISO 4217 list one published by
+the maintenance agency:
+
+	https://www.six-group.com/dam/download/financial-information/data-center/iso-currrency/lists/list-one.xml
+
+Run it from this directory with:
+
+	go run gen_iso4217.go -in list-one.xml
+
+Symbols aren't part of the standard, so they're carried over from the current
+table.
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/production-grid/pgrid-core/pkg/money"
+)
+
+type listOne struct {
+	Entries []struct {
+		Code       string `xml:"Ccy"`
+		Number     string `xml:"CcyNbr"`
+		Name       string `xml:"CcyNm"`
+		MinorUnits string `xml:"CcyMnrUnts"`
+	} `xml:"CcyTbl>CcyNtry"`
+}
+
+func main() {
+
+	in := flag.String("in", "list-one.xml", "ISO 4217 list one")
+	out := flag.String("out", "iso4217.go", "generated file")
+	flag.Parse()
+
+	raw, err := ioutil.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var list listOne
+	if err = xml.Unmarshal(raw, &list); err != nil {
+		log.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	defs := make([]money.CurrencyDefinition, 0, len(list.Entries))
+
+	for _, entry := range list.Entries {
+		places, err := strconv.Atoi(entry.MinorUnits)
+		if err != nil || entry.Code == "" || seen[entry.Code] {
+			//no minor units, no currency (Antarctica) or a duplicate country
+			continue
+		}
+		seen[entry.Code] = true
+		def := money.CurrencyDefinition{
+			Code:          entry.Code,
+			NumericCode:   entry.Number,
+			Name:          strings.TrimSpace(entry.Name),
+			DecimalPlaces: places,
+		}
+		if existing, err := money.LookupCurrency(entry.Code); err == nil {
+			def.CurrencySymbol = existing.CurrencySymbol
+		}
+		defs = append(defs, def)
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_iso4217.go from the ISO 4217 list one; DO NOT EDIT.\n\n")
+	b.WriteString("package money\n\n")
+	b.WriteString("// iso4217Currencies lists the active ISO 4217 currencies. Entries without\n")
+	b.WriteString("// minor units, such as precious metals and testing codes, are left out.\n")
+	b.WriteString("var iso4217Currencies = []CurrencyDefinition{\n")
+	for _, def := range defs {
+		fmt.Fprintf(&b, "\t{Code: %q, NumericCode: %q, Name: %q, DecimalPlaces: %d", def.Code, def.NumericCode, def.Name, def.DecimalPlaces)
+		if def.CurrencySymbol != "" {
+			fmt.Fprintf(&b, ", CurrencySymbol: %q", def.CurrencySymbol)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+}
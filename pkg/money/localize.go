@@ -0,0 +1,428 @@
+package money
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// SymbolStyle selects how the currency is written in localized amounts.
+type SymbolStyle byte
+
+// SymbolStyle constants.
+const (
+	SymbolStandard SymbolStyle = iota //the locale's symbol, e.g. CA$ for Canadian dollars in the US
+	SymbolNarrow                      //the shortest symbol, e.g. $, which can be ambiguous
+	SymbolISO                         //the ISO code, e.g. CAD
+)
+
+// DefaultLocale is used when no locale is set on a context.
+var DefaultLocale = language.AmericanEnglish
+
+// LocalizeOptions controls locale aware formatting.
+type LocalizeOptions struct {
+	Symbol     SymbolStyle
+	Accounting bool //use the locale's accounting format for negative amounts, usually parentheses
+}
+
+// currencyPattern describes where a locale puts the symbol and sign, following
+// the CLDR currency and accounting patterns, which x/text doesn't expose.
+type currencyPattern struct {
+	symbolAfter      bool //1.00 ¤ rather than ¤1.00
+	space            bool //a no-break space between the symbol and the number
+	minusAfterSymbol bool //¤ -1.00 rather than -¤ 1.00
+	negativeNoSpace  bool //drop the space when the amount is negative, ¤-1.00
+	parentheses      bool //accounting format wraps negative amounts in parentheses
+}
+
+const noBreakSpace = "\u00a0"
+
+// curatedPatterns is a hand maintained subset of the CLDR currency patterns,
+// keyed by language, or language and region where a region differs from its
+// language. Other locales use the English pattern, with their own symbols and
+// separators. Add locales here as tenants need them.
+var curatedPatterns = map[string]currencyPattern{
+	"en":     {parentheses: true},
+	"ja":     {parentheses: true},
+	"ko":     {parentheses: true},
+	"zh":     {parentheses: true},
+	"hi":     {},
+	"tr":     {},
+	"es-MX":  {parentheses: true},
+	"es-US":  {parentheses: true},
+	"es-419": {parentheses: true},
+	"fr":     {symbolAfter: true, space: true, parentheses: true},
+	"fr-CH":  {symbolAfter: true, space: true},
+	"de":     {symbolAfter: true, space: true},
+	"de-AT":  {space: true},
+	"de-CH":  {space: true, minusAfterSymbol: true, negativeNoSpace: true},
+	"de-LI":  {space: true, minusAfterSymbol: true, negativeNoSpace: true},
+	"it":     {symbolAfter: true, space: true},
+	"it-CH":  {space: true, minusAfterSymbol: true, negativeNoSpace: true},
+	"es":     {symbolAfter: true, space: true},
+	"pt":     {space: true},
+	"pt-PT":  {symbolAfter: true, space: true, parentheses: true},
+	"nl":     {space: true, minusAfterSymbol: true, parentheses: true},
+	"sv":     {symbolAfter: true, space: true},
+	"nb":     {symbolAfter: true, space: true, parentheses: true},
+	"da":     {symbolAfter: true, space: true},
+	"fi":     {symbolAfter: true, space: true},
+	"pl":     {symbolAfter: true, space: true, parentheses: true},
+	"cs":     {symbolAfter: true, space: true},
+	"sk":     {symbolAfter: true, space: true, parentheses: true},
+	"hu":     {symbolAfter: true, space: true},
+	"ro":     {symbolAfter: true, space: true, parentheses: true},
+	"ru":     {symbolAfter: true, space: true},
+	"uk":     {symbolAfter: true, space: true},
+	"el":     {symbolAfter: true, space: true},
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of the context carrying a locale, typically the
+// tenant's or user's, for formatting and parsing amounts.
+func WithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// LocaleFromContext returns the locale set with WithLocale, or DefaultLocale.
+func LocaleFromContext(ctx context.Context) language.Tag {
+
+	if ctx != nil {
+		if tag, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+			return tag
+		}
+	}
+
+	return DefaultLocale
+
+}
+
+// LocalizeContext formats the currency for the locale carried by the context.
+func (base *Currency) LocalizeContext(ctx context.Context) string {
+	return base.LocalizeFor(LocaleFromContext(ctx))
+}
+
+// LocalizeFor formats the currency for a locale using its standard symbol.
+func (base *Currency) LocalizeFor(tag language.Tag) string {
+	return base.LocalizeWith(tag, LocalizeOptions{})
+}
+
+/*
+LocalizeWith formats the currency for a locale, rounded to the currency's
+precision, with the locale's digit grouping, decimal separator, symbol
+placement and negative format. Separators and symbols come from the CLDR data
+in golang.org/x/text. Symbol placement and negative formats come from a
+curated subset of CLDR, so locales outside it are written like English, e.g.
+EUR1.234,50 in Icelandic rather than 1.234,50 EUR.
+*/
+func (base *Currency) LocalizeWith(tag language.Tag, opts LocalizeOptions) string {
+
+	code := base.CurrencyCode
+	if code == "" {
+		code = CurrencyCodeDefault
+	}
+
+	def := base.CurrencyDefinition()
+
+	rounded := *base
+	rounded.CurrencyCode = code
+	rounded = *rounded.Round(def.DecimalPlaces, RoundNearest)
+
+	p := message.NewPrinter(tag)
+
+	var b strings.Builder
+	b.WriteString(p.Sprint(number.Decimal(rounded.Integer)))
+	if def.DecimalPlaces > 0 {
+		decimal := strconv.Itoa(rounded.Decimal)
+		b.WriteString(decimalSeparator(p))
+		b.WriteString(strings.Repeat("0", def.DecimalPlaces-len(decimal)))
+		b.WriteString(decimal)
+	}
+
+	negative := rounded.Negative && !rounded.IsZero()
+
+	return lookupPattern(tag).format(currencySymbol(p, code, def, opts.Symbol), b.String(), negative, opts.Accounting)
+
+}
+
+func (pattern currencyPattern) format(symbol string, amount string, negative bool, accounting bool) string {
+
+	parentheses := negative && accounting && pattern.parentheses
+	minus := negative && !parentheses
+
+	space := ""
+	if pattern.space && !(minus && pattern.negativeNoSpace) {
+		space = noBreakSpace
+	}
+
+	var result string
+	switch {
+	case pattern.symbolAfter:
+		result = amount + space + symbol
+	case minus && pattern.minusAfterSymbol:
+		return symbol + space + "-" + amount
+	default:
+		result = symbol + space + amount
+	}
+
+	if parentheses {
+		return "(" + result + ")"
+	} else if minus {
+		return "-" + result
+	}
+
+	return result
+
+}
+
+/*
+ParseLocalizedContext parses an amount typed in the locale carried by the
+context.
+*/
+func ParseLocalizedContext(ctx context.Context, currencyCode string, text string) (*Currency, error) {
+	return ParseLocalized(LocaleFromContext(ctx), currencyCode, text)
+}
+
+/*
+ParseLocalized parses an amount as written in a locale. It accepts the
+currency's standard and narrow symbols or ISO code in any position, the
+locale's grouping and decimal separators, and negative amounts written with a
+minus sign or in parentheses. Grouping separators may only appear in the
+integer part, which must end with a group of three digits, so a grouping
+separator typed as a decimal separator, as in 12.50 in German, is rejected
+rather than read as 1250. Amounts more precise than the currency keep their
+extra decimal places.
+*/
+func ParseLocalized(tag language.Tag, currencyCode string, text string) (*Currency, error) {
+
+	def, err := LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	p := message.NewPrinter(tag)
+	value := strings.TrimSpace(text)
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	for _, style := range []SymbolStyle{SymbolISO, SymbolStandard, SymbolNarrow} {
+		if symbol := currencySymbol(p, currencyCode, def, style); strings.Contains(value, symbol) {
+			value = strings.Replace(value, symbol, "", 1)
+			break
+		}
+	}
+
+	value = strings.NewReplacer("\u200e", "", "\u200f", "", "\u2212", "-").Replace(value)
+	value = strings.Trim(value, spaces)
+
+	if strings.HasPrefix(value, "-") {
+		if negative {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+		}
+		negative = true
+		value = strings.Trim(value[1:], spaces)
+	}
+
+	//spaces left between the digits are grouping in locales that group with
+	//spaces, and invalid otherwise
+	group := groupSeparator(p)
+	if strings.Trim(group, spaces) == "" {
+		value = strings.NewReplacer(" ", group, noBreakSpace, group, "\u202f", group).Replace(value)
+	}
+
+	if group == "\u2019" {
+		value = strings.Replace(value, "'", group, -1)
+	}
+
+	separator := decimalSeparator(p)
+	if !validGrouping(value, group, separator) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+	value = strings.Replace(value, group, "", -1)
+
+	result, err := parseDecimal(def, value, separator, negative)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, text)
+	}
+
+	return result, nil
+
+}
+
+// spaces are the space characters locales write around symbols and
+// between digit groups.
+const spaces = " " + noBreakSpace + "\u202f"
+
+/*
+validGrouping reports whether grouping separators only appear before the
+decimal separator, with groups of two or three digits between them and
+exactly three after the last. That allows both the usual groups of three and
+the Indian groups of two.
+*/
+func validGrouping(value string, group string, separator string) bool {
+
+	if group == "" || !strings.Contains(value, group) {
+		return true
+	}
+
+	integer := value
+	if idx := strings.Index(value, separator); idx >= 0 {
+		integer = value[:idx]
+		if strings.Contains(value[idx:], group) {
+			return false
+		}
+	}
+
+	groups := strings.Split(integer, group)
+	for i, digits := range groups {
+		switch {
+		case i == 0:
+			if digits == "" {
+				return false
+			}
+		case i == len(groups)-1:
+			if len(digits) != 3 {
+				return false
+			}
+		case len(digits) < 2 || len(digits) > 3:
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// parseDecimal parses unsigned digits with an optional decimal separator and
+// no grouping. Bad input returns ErrInvalidAmount, unwrapped so callers can
+// add context.
+func parseDecimal(def CurrencyDefinition, digits string, separator string, negative bool) (*Currency, error) {
+
+	parts := strings.Split(digits, separator)
+	if len(parts) > 2 || parts[0] == "" && (len(parts) == 1 || parts[1] == "") {
+		return nil, ErrInvalidAmount
+	}
+
+	for _, part := range parts {
+		if strings.Trim(part, "0123456789") != "" {
+			return nil, ErrInvalidAmount
+		}
+	}
+
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+
+	return fromDecimalParts(def, parts[0], fraction, negative)
+
+}
+
+// fromDecimalParts builds a currency from the digits either side of the
+// decimal separator.
+func fromDecimalParts(def CurrencyDefinition, integer string, fraction string, negative bool) (*Currency, error) {
+
+	places := def.DecimalPlaces
+	if len(fraction) > places {
+		places = len(fraction)
+	}
+
+	if places > maxDecimalPlaces {
+		return nil, ErrOverflow
+	}
+
+	result := Currency{CurrencyCode: def.Code, DecimalDenominator: pow10(places)}
+
+	var err error
+	if integer != "" {
+		if result.Integer, err = strconv.Atoi(integer); err != nil {
+			return nil, ErrOverflow
+		}
+	}
+
+	if fraction != "" {
+		if result.Decimal, err = strconv.Atoi(fraction + strings.Repeat("0", places-len(fraction))); err != nil {
+			return nil, ErrOverflow
+		}
+	}
+
+	result.Negative = negative && !result.IsZero()
+
+	return &result, nil
+
+}
+
+// lookupPattern finds the curated currency pattern for a locale, falling back
+// to its language, then English.
+func lookupPattern(tag language.Tag) currencyPattern {
+
+	base, _ := tag.Base()
+	region, confidence := tag.Region()
+
+	if confidence != language.No {
+		if pattern, ok := curatedPatterns[base.String()+"-"+region.String()]; ok {
+			return pattern
+		}
+	}
+
+	if pattern, ok := curatedPatterns[base.String()]; ok {
+		return pattern
+	}
+
+	return curatedPatterns["en"]
+
+}
+
+// currencySymbol returns the symbol for a currency in the given style,
+// falling back to the definition's symbol, then the code, for currencies
+// outside the CLDR data.
+func currencySymbol(p *message.Printer, code string, def CurrencyDefinition, style SymbolStyle) string {
+
+	if style == SymbolISO {
+		return code
+	}
+
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		if def.CurrencySymbol != "" {
+			return def.CurrencySymbol
+		}
+		return code
+	}
+
+	if style == SymbolNarrow {
+		return p.Sprint(currency.NarrowSymbol(unit))
+	}
+
+	return p.Sprint(currency.Symbol(unit))
+
+}
+
+// decimalSeparator returns the locale's decimal separator.
+func decimalSeparator(p *message.Printer) string {
+
+	formatted := p.Sprint(number.Decimal(1.5, number.Scale(1)))
+
+	return strings.TrimSuffix(strings.TrimPrefix(formatted, "1"), "5")
+
+}
+
+// groupSeparator returns the locale's digit grouping separator.
+func groupSeparator(p *message.Printer) string {
+
+	formatted := p.Sprint(number.Decimal(1000))
+
+	return strings.TrimSuffix(strings.TrimPrefix(formatted, "1"), "000")
+
+}
@@ -0,0 +1,186 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizeFor(t *testing.T) {
+
+	amount := func(code string) *Currency {
+		return &Currency{CurrencyCode: code, Integer: 1234567, Decimal: 505, DecimalDenominator: 1000, Negative: true}
+	}
+
+	tests := []struct {
+		tag      string
+		code     string
+		expected string
+	}{
+		{"en-US", "USD", "-$1,234,567.51"},
+		{"en-US", "CAD", "-CA$1,234,567.51"},
+		{"en-CA", "CAD", "-$1,234,567.51"},
+		{"fr-CA", "CAD", "-1\u00a0234\u00a0567,51\u00a0$"},
+		{"fr-CA", "USD", "-1\u00a0234\u00a0567,51\u00a0$\u00a0US"},
+		{"de", "EUR", "-1.234.567,51\u00a0€"},
+		{"de-AT", "EUR", "-€\u00a01\u00a0234\u00a0567,51"},
+		{"de-CH", "CHF", "CHF-1’234’567.51"},
+		{"nl", "EUR", "€\u00a0-1.234.567,51"},
+		{"ja", "JPY", "-￥1,234,568"},
+		{"en-IN", "INR", "-₹12,34,567.51"},
+		{"pt-BR", "BRL", "-R$\u00a01.234.567,51"},
+		{"de", "BTC", "-1.234.567,50500000\u00a0₿"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, amount(test.code).LocalizeFor(language.MustParse(test.tag)), test.tag+" "+test.code)
+	}
+
+	positive := &Currency{CurrencyCode: "CHF", Integer: 12, Decimal: 50, DecimalDenominator: 100}
+	assert.Equal(t, "CHF\u00a012.50", positive.LocalizeFor(language.MustParse("de-CH")))
+
+	negativeZero := &Currency{CurrencyCode: "EUR", Decimal: 1, DecimalDenominator: 1000, Negative: true}
+	assert.Equal(t, "0,00\u00a0€", negativeZero.LocalizeFor(language.German))
+
+}
+
+func TestLocalizeWith(t *testing.T) {
+
+	refund := &Currency{CurrencyCode: "CAD", Integer: 1234, Decimal: 5, DecimalDenominator: 100, Negative: true}
+
+	assert.Equal(t, "-$1,234.05", refund.LocalizeWith(language.AmericanEnglish, LocalizeOptions{Symbol: SymbolNarrow}))
+	assert.Equal(t, "-CAD1,234.05", refund.LocalizeWith(language.AmericanEnglish, LocalizeOptions{Symbol: SymbolISO}))
+	assert.Equal(t, "(CA$1,234.05)", refund.LocalizeWith(language.AmericanEnglish, LocalizeOptions{Accounting: true}))
+	assert.Equal(t, "(1\u00a0234,05\u00a0$)", refund.LocalizeWith(language.CanadianFrench, LocalizeOptions{Accounting: true}))
+
+	//German has no accounting format, so negative amounts keep the minus sign
+	assert.Equal(t, "-1.234,05\u00a0CA$", refund.LocalizeWith(language.German, LocalizeOptions{Accounting: true}))
+
+}
+
+func TestParseLocalized(t *testing.T) {
+
+	tests := []struct {
+		tag      string
+		code     string
+		text     string
+		expected string
+	}{
+		{"en-US", "USD", "$1,234.50", "1234.50"},
+		{"en-US", "USD", "($1,234.50)", "-1234.50"},
+		{"en-US", "USD", "-1234.5", "-1234.50"},
+		{"en-US", "CAD", "CA$12.00", "12.00"},
+		{"en-US", "CAD", "CAD 12", "12.00"},
+		{"fr-CA", "CAD", "1 234,56 $", "1234.56"},
+		{"fr-CA", "CAD", "-1\u00a0234,56\u00a0$", "-1234.56"},
+		{"fr-CA", "CAD", "1\u202f234,56", "1234.56"},
+		{"de", "EUR", "1.234,56 €", "1234.56"},
+		{"de", "EUR", "−7,5", "-7.50"},
+		{"de-CH", "CHF", "CHF-1’234.50", "-1234.50"},
+		{"de-CH", "CHF", "1'234.50", "1234.50"},
+		{"nl", "EUR", "€ -1.234,56", "-1234.56"},
+		{"en-IN", "INR", "₹12,34,567.5", "1234567.50"},
+		{"en-US", "USD", "0.125", "0.125"},
+		{"en-US", "USD", ".99", "0.99"},
+	}
+
+	for _, test := range tests {
+		result, err := ParseLocalized(language.MustParse(test.tag), test.code, test.text)
+		if assert.NoError(t, err, test.text) {
+			assert.Equal(t, test.code, result.CurrencyCode)
+			assert.Equal(t, 0, compareScaled(ParseCurrency("USD", test.expected), result), test.text+" parsed as "+result.FormatCurrency())
+		}
+	}
+
+	for _, text := range []string{"", "$", "1.234.5", "12abc", "(-5)", "1,23", "1,2345.00", "1.00,000", ",123", "1,23,4,5"} {
+		_, err := ParseLocalized(language.AmericanEnglish, "USD", text)
+		assert.True(t, errors.Is(err, ErrInvalidAmount), text)
+	}
+
+	//a grouping separator typed as a decimal separator isn't read as grouping
+	for _, text := range []string{"12.50 €", "1.234.5", "1.23,50", "12 50"} {
+		_, err := ParseLocalized(language.German, "EUR", text)
+		assert.True(t, errors.Is(err, ErrInvalidAmount), text)
+	}
+
+	_, err := ParseLocalized(language.French, "EUR", "12 50 €")
+	assert.True(t, errors.Is(err, ErrInvalidAmount))
+
+	_, err = ParseLocalized(language.AmericanEnglish, "XYZ", "1.00")
+	assert.True(t, errors.Is(err, ErrUnsupportedCurrency))
+
+	_, err = ParseLocalized(language.AmericanEnglish, "USD", "99999999999999999999")
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+}
+
+func TestLocalizeRoundTrip(t *testing.T) {
+
+	for _, tag := range []string{"en-US", "fr-CA", "de", "de-CH", "nl", "ja", "en-IN", "pt-BR", "sv"} {
+		for _, code := range []string{"USD", "CAD", "EUR", "JPY", "CHF", "BTC"} {
+			lang := language.MustParse(tag)
+			def, _ := LookupCurrency(code)
+			original := &Currency{CurrencyCode: code, Integer: 9876543, Decimal: 1, DecimalDenominator: pow10(def.DecimalPlaces), Negative: true}
+			if def.DecimalPlaces == 0 {
+				original.Decimal = 0
+			}
+			for _, opts := range []LocalizeOptions{{}, {Symbol: SymbolNarrow}, {Symbol: SymbolISO}, {Accounting: true}} {
+				text := original.LocalizeWith(lang, opts)
+				parsed, err := ParseLocalized(lang, code, text)
+				if assert.NoError(t, err, text) {
+					assert.True(t, original.Equals(parsed), tag+" "+text)
+				}
+			}
+		}
+	}
+
+}
+
+func TestLocaleContext(t *testing.T) {
+
+	amount := &Currency{CurrencyCode: "EUR", Integer: 1234, Decimal: 50, DecimalDenominator: 100}
+
+	assert.Equal(t, DefaultLocale, LocaleFromContext(context.Background()))
+	assert.Equal(t, "€1,234.50", amount.LocalizeContext(context.Background()))
+
+	ctx := WithLocale(context.Background(), language.German)
+	assert.Equal(t, language.German, LocaleFromContext(ctx))
+	assert.Equal(t, "1.234,50\u00a0€", amount.LocalizeContext(ctx))
+
+	parsed, err := ParseLocalizedContext(ctx, "EUR", "1.234,50 €")
+	if assert.NoError(t, err) {
+		assert.True(t, amount.Equals(parsed))
+	}
+
+}
+
+func TestUncuratedLocales(t *testing.T) {
+
+	amount := &Currency{CurrencyCode: "EUR", Integer: 1234, Decimal: 50, DecimalDenominator: 100, Negative: true}
+
+	//locales outside the curated patterns use their language's pattern, then
+	//English, with their own separators
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"fr-BE", "-1\u00a0234,50\u00a0€"},
+		{"is", "-EUR1.234,50"},
+		{"ga", "-€1,234.50"},
+		{"sw", "-€1,234.50"},
+	}
+
+	for _, test := range tests {
+		tag := language.MustParse(test.tag)
+		formatted := amount.LocalizeFor(tag)
+		assert.Equal(t, test.expected, formatted, test.tag)
+		parsed, err := ParseLocalized(tag, "EUR", formatted)
+		if assert.NoError(t, err, test.tag) {
+			assert.True(t, amount.Equals(parsed), test.tag)
+		}
+	}
+
+}
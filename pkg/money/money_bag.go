@@ -0,0 +1,219 @@
+package money
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+/*
+MoneyBag holds running totals in several currencies, such as the takings of
+a box office that accepts foreign cards, without converting between them.
+The zero value is an empty bag ready to use.
+*/
+type MoneyBag struct {
+	totals map[string]*Currency
+}
+
+/*
+NewMoneyBag returns a bag holding the given amounts, or an error if one of
+them is nil or in an unsupported currency.
+*/
+func NewMoneyBag(values ...*Currency) (*MoneyBag, error) {
+
+	bag := &MoneyBag{}
+
+	for _, value := range values {
+		if err := bag.Add(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return bag, nil
+
+}
+
+/*
+Add adds an amount to the total for its currency. Amounts without a currency
+code are taken to be in the default currency.
+*/
+func (bag *MoneyBag) Add(value *Currency) error {
+	return bag.accumulate(value, false)
+}
+
+// Subtract subtracts an amount from the total for its currency.
+func (bag *MoneyBag) Subtract(value *Currency) error {
+	return bag.accumulate(value, true)
+}
+
+func (bag *MoneyBag) accumulate(value *Currency, subtract bool) error {
+
+	if err := checkValue(0, value); err != nil {
+		return err
+	}
+
+	if value.IsZero() {
+		return nil
+	}
+
+	operand := *value
+	if operand.CurrencyCode == "" {
+		operand.CurrencyCode = CurrencyCodeDefault
+	}
+	if subtract {
+		operand.Negative = !operand.Negative
+	}
+
+	if _, err := operand.definition(); err != nil {
+		return err
+	}
+
+	operand.normalize()
+
+	if bag.totals == nil {
+		bag.totals = make(map[string]*Currency)
+	}
+
+	total, ok := bag.totals[operand.CurrencyCode]
+	if !ok {
+		bag.totals[operand.CurrencyCode] = &operand
+		return nil
+	}
+
+	total, err := total.AddChecked(&operand)
+	if err != nil {
+		return err
+	}
+
+	bag.totals[operand.CurrencyCode] = total
+
+	return nil
+
+}
+
+// Negate returns a bag with the sign of every total flipped.
+func (bag *MoneyBag) Negate() *MoneyBag {
+
+	result := &MoneyBag{totals: make(map[string]*Currency, len(bag.totals))}
+
+	for code, total := range bag.totals {
+		result.totals[code] = total.Negate()
+	}
+
+	return result
+
+}
+
+// IsZero returns true if every total is zero.
+func (bag *MoneyBag) IsZero() bool {
+
+	for _, total := range bag.totals {
+		if !total.IsZero() {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+/*
+Get returns a copy of the total for a currency, which is zero in that
+currency if the bag holds none of it.
+*/
+func (bag *MoneyBag) Get(currencyCode string) *Currency {
+
+	if total, ok := bag.totals[currencyCode]; ok {
+		result := *total
+		return &result
+	}
+
+	return &Currency{CurrencyCode: currencyCode}
+
+}
+
+// Amounts returns copies of the non-zero totals, ordered by currency code.
+func (bag *MoneyBag) Amounts() []*Currency {
+
+	codes := make([]string, 0, len(bag.totals))
+	for code, total := range bag.totals {
+		if !total.IsZero() {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+
+	results := make([]*Currency, len(codes))
+	for i, code := range codes {
+		results[i] = bag.Get(code)
+	}
+
+	return results
+
+}
+
+/*
+String lists the non-zero totals in the CodeString format, ordered by
+currency code, e.g. EUR 12.50, USD 3.00. An empty bag is written as 0.
+*/
+func (bag *MoneyBag) String() string {
+
+	return bag.format(func(total *Currency) string {
+		return total.CodeString()
+	})
+
+}
+
+// LocalizeFor lists the non-zero totals formatted for a locale.
+func (bag *MoneyBag) LocalizeFor(tag language.Tag) string {
+
+	return bag.format(func(total *Currency) string {
+		return total.LocalizeFor(tag)
+	})
+
+}
+
+func (bag *MoneyBag) format(formatter func(*Currency) string) string {
+
+	amounts := bag.Amounts()
+	if len(amounts) == 0 {
+		return "0"
+	}
+
+	parts := make([]string, len(amounts))
+	for i, amount := range amounts {
+		parts[i] = formatter(amount)
+	}
+
+	return strings.Join(parts, ", ")
+
+}
+
+/*
+Convert converts every total into one currency at the provider's rates in
+effect at the given time and returns their sum. The sum is exact until it's
+rounded once, to the target currency's decimal places, with the given mode.
+*/
+func (bag *MoneyBag) Convert(to string, provider RateProvider, at time.Time, mode RoundingMode) (*Currency, error) {
+
+	sum := new(big.Rat)
+
+	for _, total := range bag.Amounts() {
+		amount := total.Rat()
+		if total.CurrencyCode != to {
+			rate, err := provider.Rate(total.CurrencyCode, to, at)
+			if err != nil {
+				return nil, err
+			}
+			amount.Mul(amount, rate.Rate)
+		}
+		sum.Add(sum, amount)
+	}
+
+	return FromRat(to, sum, mode)
+
+}
@@ -0,0 +1,49 @@
+package money
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestMoneyBag(t *testing.T) {
+
+	assert := assert.New(t)
+
+	bag, err := NewMoneyBag(
+		ParseCurrency(CurrencyCodeUSD, "10.00"),
+		ParseCurrency(CurrencyCodeCAD, "20.00"),
+		Zero(),
+	)
+	assert.NoError(err)
+	assert.NoError(bag.Add(ParseCurrency(CurrencyCodeUSD, "2.50")))
+	assert.NoError(bag.Subtract(ParseCurrency(CurrencyCodeCAD, "5.25")))
+	assert.NoError(bag.Add(ParseCurrency("EUR", "0.01")))
+	assert.NoError(bag.Subtract(ParseCurrency("EUR", "0.01")))
+
+	assert.False(bag.IsZero())
+	assert.Equal("12.50", bag.Get(CurrencyCodeUSD).FormatCurrency())
+	assert.True(bag.Get("JPY").IsZero())
+	assert.Equal("CAD 14.75, USD 12.50", bag.String())
+	assert.Equal("CA$14.75, $12.50", bag.LocalizeFor(language.AmericanEnglish))
+	assert.Equal("CAD -14.75, USD -12.50", bag.Negate().String())
+
+	var empty MoneyBag
+	assert.True(empty.IsZero())
+	assert.Equal("0", empty.String())
+	assert.True(errors.Is(empty.Add(&Currency{Integer: 1, CurrencyCode: "XYZ"}), ErrUnsupportedCurrency))
+
+	provider := NewStaticRateProvider()
+	assert.NoError(provider.Add(CurrencyCodeCAD, CurrencyCodeUSD, "0.7345", time.Time{}))
+
+	converted, err := bag.Convert(CurrencyCodeUSD, provider, time.Now(), RoundNearest)
+	assert.NoError(err)
+	assert.Equal("23.33", converted.FormatCurrency())
+
+	_, err = bag.Convert("EUR", provider, time.Now(), RoundNearest)
+	assert.True(errors.Is(err, ErrRateNotFound))
+
+}
@@ -0,0 +1,120 @@
+package money
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/database/sqltrace"
+)
+
+// ExchangeRateSchemaFile declares the exchange_rates table. Modules that use
+// TableRateProvider should include it in their schema files.
+const ExchangeRateSchemaFile = "schema/exchange_rates.json"
+
+// rateDigits is the number of digits the exchange_rates table keeps for a
+// rate's numerator and denominator.
+const rateDigits = 40
+
+/*
+TableRateProvider serves historical exchange rates from the exchange_rates
+table. Like StaticRateProvider, a pair without its own rates falls back to
+the inverse of the opposite pair.
+*/
+type TableRateProvider struct {
+	DB     sqltrace.Querier
+	Target string //the statement target reported to sqltrace, usually replica
+}
+
+// NewTableRateProvider returns a provider that reads rates through the given
+// database or transaction.
+func NewTableRateProvider(db sqltrace.Querier) *TableRateProvider {
+	return &TableRateProvider{DB: db, Target: sqltrace.TargetReplica}
+}
+
+// Rate implements RateProvider.
+func (provider *TableRateProvider) Rate(from string, to string, at time.Time) (ExchangeRate, error) {
+
+	rate, found, err := provider.find(from, to, at)
+	if err != nil || found {
+		return rate, err
+	}
+
+	rate, found, err = provider.find(to, from, at)
+	if err != nil {
+		return rate, err
+	}
+	if found {
+		return rate.Inverse(), nil
+	}
+
+	return ExchangeRate{}, fmt.Errorf("%w: %v to %v at %v", ErrRateNotFound, from, to, at.Format(time.RFC3339))
+
+}
+
+func (provider *TableRateProvider) find(from string, to string, at time.Time) (ExchangeRate, bool, error) {
+
+	query := "SELECT rate_numerator, rate_denominator, effective_at FROM exchange_rates WHERE from_currency = $1 AND to_currency = $2 AND effective_at <= $3 ORDER BY effective_at DESC LIMIT 1"
+
+	rows, err := sqltrace.Query(provider.DB, provider.Target, query, from, to, at.UTC())
+	defer rows.Close()
+	if err != nil {
+		return ExchangeRate{}, false, err
+	}
+
+	if !rows.Next() {
+		return ExchangeRate{}, false, rows.Err()
+	}
+
+	var numerator, denominator string
+	result := ExchangeRate{From: from, To: to}
+	if err = rows.Scan(&numerator, &denominator, &result.EffectiveAt); err != nil {
+		return ExchangeRate{}, false, err
+	}
+
+	result.Rate, err = ParseRate(numerator + "/" + denominator)
+	if err != nil {
+		return ExchangeRate{}, false, err
+	}
+
+	return result, true, nil
+
+}
+
+/*
+SaveExchangeRateWithTx stores an exchange rate in the exchange_rates table,
+replacing any rate for the same pair and effective time. Rates are stored
+exactly, as a numerator and denominator of up to forty digits each; larger
+ones return ErrInvalidRate.
+*/
+func SaveExchangeRateWithTx(tx *sql.Tx, rate ExchangeRate) error {
+
+	numerator, denominator, err := storedRate(rate)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO exchange_rates (from_currency, to_currency, effective_at, rate_numerator, rate_denominator) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (from_currency, to_currency, effective_at) DO UPDATE SET rate_numerator = EXCLUDED.rate_numerator, rate_denominator = EXCLUDED.rate_denominator"
+
+	_, err = sqltrace.Exec(tx, sqltrace.TargetPrimary, query, rate.From, rate.To, rate.EffectiveAt.UTC(), numerator, denominator)
+
+	return err
+
+}
+
+// storedRate returns a rate's numerator and denominator as stored, rejecting
+// rates that don't fit the table's columns.
+func storedRate(rate ExchangeRate) (string, string, error) {
+
+	if rate.Rate == nil || rate.Rate.Sign() <= 0 {
+		return "", "", ErrInvalidRate
+	}
+
+	numerator, denominator := rate.Rate.Num().String(), rate.Rate.Denom().String()
+	if len(numerator) > rateDigits || len(denominator) > rateDigits {
+		return "", "", fmt.Errorf("%w: %v to %v rate %v has more than %v digits", ErrInvalidRate, rate.From, rate.To, rate.Rate.RatString(), rateDigits)
+	}
+
+	return numerator, denominator, nil
+
+}
@@ -0,0 +1,225 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exchange rate errors.
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidRate  = errors.New("exchange rates must be positive decimals")
+)
+
+/*
+ExchangeRate is the number of units of the To currency one unit of the From
+currency buys, in effect from EffectiveAt until the pair's next rate.
+*/
+type ExchangeRate struct {
+	From        string
+	To          string
+	Rate        *big.Rat
+	EffectiveAt time.Time
+}
+
+/*
+RateProvider looks up exchange rates. Rate returns the rate between two
+currencies in effect at the given time, or an error wrapping ErrRateNotFound.
+*/
+type RateProvider interface {
+	Rate(from string, to string, at time.Time) (ExchangeRate, error)
+}
+
+// ParseRate parses an exchange rate written as a decimal, e.g. 1.3482.
+func ParseRate(value string) (*big.Rat, error) {
+
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+
+	return rate, nil
+
+}
+
+// Inverse returns the rate for converting in the opposite direction.
+func (rate ExchangeRate) Inverse() ExchangeRate {
+
+	return ExchangeRate{
+		From:        rate.To,
+		To:          rate.From,
+		Rate:        new(big.Rat).Inv(rate.Rate),
+		EffectiveAt: rate.EffectiveAt,
+	}
+
+}
+
+/*
+Convert converts the currency into another currency at the provider's rate in
+effect at the given time. The result is rounded to the target currency's
+decimal places with the given rounding mode. Converting into the same
+currency returns a copy without consulting the provider.
+*/
+func (base *Currency) Convert(to string, provider RateProvider, at time.Time, mode RoundingMode) (*Currency, error) {
+
+	from := base.CurrencyCode
+	if from == "" {
+		from = CurrencyCodeDefault
+	}
+
+	if from == to {
+		result := *base
+		result.CurrencyCode = to
+		return &result, nil
+	}
+
+	rate, err := provider.Rate(from, to, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return base.ConvertAt(rate, mode)
+
+}
+
+/*
+ConvertAt converts the currency at the given exchange rate, rounding the
+result to the target currency's decimal places with the given rounding mode.
+*/
+func (base *Currency) ConvertAt(rate ExchangeRate, mode RoundingMode) (*Currency, error) {
+
+	def, err := LookupCurrency(rate.To)
+	if err != nil {
+		return nil, err
+	}
+
+	from := base.CurrencyCode
+	if from == "" {
+		from = CurrencyCodeDefault
+	}
+
+	if from != rate.From && !base.IsZero() {
+		return nil, fmt.Errorf("%w: %v amount converted at a %v rate", ErrCurrencyMismatch, from, rate.From)
+	}
+
+	denom := base.DecimalDenominator
+	if denom == 0 {
+		denom = 1
+	}
+
+	targetDenom := pow10(def.DecimalPlaces)
+
+	//amount * rate, expressed in the target currency's smallest unit
+	numerator := new(big.Int).Mul(base.scaled(), rate.Rate.Num())
+	numerator.Mul(numerator, big.NewInt(int64(targetDenom)))
+	divisor := new(big.Int).Mul(big.NewInt(int64(denom)), rate.Rate.Denom())
+
+	return fromScaledChecked(rate.To, roundQuo(numerator, divisor, mode), targetDenom)
+
+}
+
+type currencyPair struct {
+	from string
+	to   string
+}
+
+/*
+StaticRateProvider serves exchange rates held in memory, for tests, fixed
+contractual rates and rates loaded from a file. Each pair can have several
+rates with different effective times. A pair without its own rates falls back
+to the inverse of the opposite pair.
+*/
+type StaticRateProvider struct {
+	mu    sync.RWMutex
+	rates map[currencyPair][]ExchangeRate //ordered by effective time
+}
+
+// NewStaticRateProvider returns an empty static rate provider.
+func NewStaticRateProvider() *StaticRateProvider {
+	return &StaticRateProvider{rates: make(map[currencyPair][]ExchangeRate)}
+}
+
+/*
+Add parses and adds a rate for converting from one currency to another,
+effective from the given time. Use the zero time for a rate that has always
+applied. A rate with the same effective time replaces the existing one.
+*/
+func (provider *StaticRateProvider) Add(from string, to string, rate string, effectiveAt time.Time) error {
+
+	parsed, err := ParseRate(rate)
+	if err != nil {
+		return err
+	}
+
+	provider.Set(ExchangeRate{From: from, To: to, Rate: parsed, EffectiveAt: effectiveAt})
+
+	return nil
+
+}
+
+// Set adds an exchange rate, replacing any rate for the same pair and
+// effective time.
+func (provider *StaticRateProvider) Set(rate ExchangeRate) {
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	pair := currencyPair{rate.From, rate.To}
+	rates := provider.rates[pair]
+
+	idx := sort.Search(len(rates), func(i int) bool {
+		return !rates[i].EffectiveAt.Before(rate.EffectiveAt)
+	})
+
+	if idx < len(rates) && rates[idx].EffectiveAt.Equal(rate.EffectiveAt) {
+		rates[idx] = rate
+		return
+	}
+
+	rates = append(rates, ExchangeRate{})
+	copy(rates[idx+1:], rates[idx:])
+	rates[idx] = rate
+
+	provider.rates[pair] = rates
+
+}
+
+// Rate implements RateProvider.
+func (provider *StaticRateProvider) Rate(from string, to string, at time.Time) (ExchangeRate, error) {
+
+	provider.mu.RLock()
+	defer provider.mu.RUnlock()
+
+	if rate, ok := provider.find(from, to, at); ok {
+		return rate, nil
+	}
+
+	if rate, ok := provider.find(to, from, at); ok {
+		return rate.Inverse(), nil
+	}
+
+	return ExchangeRate{}, fmt.Errorf("%w: %v to %v at %v", ErrRateNotFound, from, to, at.Format(time.RFC3339))
+
+}
+
+// find returns the latest rate for a pair that's effective at the given time.
+func (provider *StaticRateProvider) find(from string, to string, at time.Time) (ExchangeRate, bool) {
+
+	rates := provider.rates[currencyPair{from, to}]
+
+	idx := sort.Search(len(rates), func(i int) bool {
+		return rates[i].EffectiveAt.After(at)
+	})
+
+	if idx == 0 {
+		return ExchangeRate{}, false
+	}
+
+	return rates[idx-1], true
+
+}
@@ -0,0 +1,133 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRateProvider(t *testing.T) {
+
+	july := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	august := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	provider := NewStaticRateProvider()
+	assert.NoError(t, provider.Add("CAD", "USD", "0.75", august))
+	assert.NoError(t, provider.Add("CAD", "USD", "0.7", july))
+	assert.True(t, errors.Is(provider.Add("CAD", "USD", "-1", july), ErrInvalidRate))
+	assert.True(t, errors.Is(provider.Add("CAD", "USD", "abc", july), ErrInvalidRate))
+
+	rate, err := provider.Rate("CAD", "USD", july.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.70", rate.Rate.FloatString(2))
+	assert.Equal(t, july, rate.EffectiveAt)
+
+	rate, err = provider.Rate("CAD", "USD", august.AddDate(1, 0, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.75", rate.Rate.FloatString(2))
+
+	//the opposite pair is inverted
+	rate, err = provider.Rate("USD", "CAD", august)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", rate.From)
+	assert.Equal(t, "CAD", rate.To)
+	assert.Equal(t, "4/3", rate.Rate.String())
+
+	_, err = provider.Rate("CAD", "USD", july.Add(-time.Second))
+	assert.True(t, errors.Is(err, ErrRateNotFound))
+
+	_, err = provider.Rate("CAD", "EUR", august)
+	assert.True(t, errors.Is(err, ErrRateNotFound))
+
+	//replacing a rate
+	assert.NoError(t, provider.Add("CAD", "USD", "0.72", july))
+	rate, err = provider.Rate("CAD", "USD", july)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.72", rate.Rate.FloatString(2))
+
+}
+
+func TestConvert(t *testing.T) {
+
+	provider := NewStaticRateProvider()
+	assert.NoError(t, provider.Add("CAD", "USD", "0.7345", time.Time{}))
+	assert.NoError(t, provider.Add("USD", "JPY", "106.92", time.Time{}))
+	assert.NoError(t, provider.Add("BTC", "USD", "9123.45", time.Time{}))
+
+	now := time.Now()
+
+	ticket := ParseCurrency("CAD", "125.55")
+
+	converted, err := ticket.Convert("USD", provider, now, RoundNearest)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", converted.CurrencyCode)
+	assert.Equal(t, "92.22", converted.FormatCurrency())
+
+	converted, err = ticket.Convert("USD", provider, now, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "92.21", converted.FormatCurrency())
+
+	converted, err = ticket.Negate().Convert("USD", provider, now, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "-92.22", converted.FormatCurrency())
+
+	//inverse rates are exact until the final rounding
+	converted, err = ParseCurrency("USD", "92.22").Convert("CAD", provider, now, RoundNearest)
+	assert.NoError(t, err)
+	assert.Equal(t, "125.55", converted.FormatCurrency())
+
+	converted, err = ParseCurrency("USD", "10.00").Convert("JPY", provider, now, RoundNearest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1069, converted.Integer)
+	assert.Equal(t, 0, converted.Decimal)
+
+	converted, err = ParseCurrency("BTC", "0.00012345").Convert("USD", provider, now, RoundUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.13", converted.FormatCurrency())
+
+	same, err := ticket.Convert("CAD", nil, now, RoundNearest)
+	assert.NoError(t, err)
+	assert.True(t, ticket.Equals(same))
+
+	_, err = ticket.Convert("EUR", provider, now, RoundNearest)
+	assert.True(t, errors.Is(err, ErrRateNotFound))
+
+	rate, _ := provider.Rate("USD", "JPY", now)
+	_, err = ticket.ConvertAt(rate, RoundNearest)
+	assert.True(t, errors.Is(err, ErrCurrencyMismatch))
+
+	_, err = ParseCurrency("USD", "90000000000000000").Convert("JPY", provider, now, RoundNearest)
+	assert.Equal(t, ErrOverflow, err)
+
+}
+
+func TestStoredRate(t *testing.T) {
+
+	numerator, denominator, err := storedRate(ExchangeRate{From: "CAD", To: "USD", Rate: big.NewRat(7345, 10000)})
+	assert.NoError(t, err)
+	assert.Equal(t, "1469", numerator)
+	assert.Equal(t, "2000", denominator)
+
+	//small rates keep every significant digit
+	dong, _ := ParseRate("0.0000393258427")
+	numerator, denominator, err = storedRate(ExchangeRate{From: "VND", To: "USD", Rate: dong})
+	assert.NoError(t, err)
+	stored, err := ParseRate(numerator + "/" + denominator)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dong.Cmp(stored))
+
+	_, _, err = storedRate(ExchangeRate{From: "JPY", To: "BTC", Rate: big.NewRat(1, 9000000000000)})
+	assert.NoError(t, err)
+
+	huge, _ := new(big.Int).SetString("1"+strings.Repeat("0", rateDigits), 10)
+	_, _, err = storedRate(ExchangeRate{From: "JPY", To: "BTC", Rate: new(big.Rat).SetFrac(big.NewInt(1), huge)})
+	assert.True(t, errors.Is(err, ErrInvalidRate))
+
+	_, _, err = storedRate(ExchangeRate{From: "CAD", To: "USD"})
+	assert.Equal(t, ErrInvalidRate, err)
+
+}
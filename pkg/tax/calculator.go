@@ -0,0 +1,413 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/production-grid/pgrid-core/pkg/money"
+)
+
+// Rounding selects where tax amounts are rounded to the currency's precision.
+type Rounding byte
+
+// Rounding constants.
+const (
+	RoundPerLine    Rounding = iota //round each line's taxes, then add them up
+	RoundPerInvoice                 //add up the exact taxes, round the totals, then spread them over the lines
+)
+
+// Tax calculation errors.
+var (
+	ErrNoLines      = errors.New("tax calculations need at least one line")
+	ErrUnreconciled = errors.New("tax breakdown doesn't reconcile")
+)
+
+/*
+Calculator calculates the taxes on an order in a jurisdiction. Inclusive
+calculators treat line amounts as prices that already include tax, which is
+backed out of them; otherwise tax is added on top. Mode rounds tax amounts to
+the currency's precision, at the level chosen by Rounding.
+*/
+type Calculator struct {
+	Jurisdiction Jurisdiction
+	Inclusive    bool
+	Rounding     Rounding
+	Mode         money.RoundingMode
+}
+
+/*
+Line is an order line to tax. The amount is the extended price, quantity
+included, and is rounded to the currency's precision before tax is
+calculated.
+*/
+type Line struct {
+	Reference string
+	Category  Category
+	Amount    *money.Currency
+}
+
+// TaxAmount is one tax charged on a line.
+type TaxAmount struct {
+	Name   string
+	Rate   *money.BasisPoints
+	Amount *money.Currency
+}
+
+/*
+LineTax is the tax on one line. Taxes has an entry for every rate in the
+jurisdiction, in order, including rates that don't apply to the line's
+category.
+*/
+type LineTax struct {
+	Line  Line
+	Net   *money.Currency
+	Taxes []TaxAmount
+	Tax   *money.Currency
+	Total *money.Currency
+}
+
+// TaxTotal is the total of one tax across an order, along with the net
+// amount of the lines it was charged on.
+type TaxTotal struct {
+	Name    string
+	Taxable *money.Currency
+	Amount  *money.Currency
+}
+
+/*
+Breakdown itemizes the taxes on an order. It reconciles exactly: the line
+taxes add up to the line tax totals and the tax totals, the nets and taxes
+add up to the totals, and for inclusive prices the line totals are the
+original line amounts.
+*/
+type Breakdown struct {
+	Lines []LineTax
+	Taxes []TaxTotal
+	Net   *money.Currency
+	Tax   *money.Currency
+	Total *money.Currency
+}
+
+// calculation holds the state of one Calculate call, with amounts in the
+// currency's smallest unit.
+type calculation struct {
+	calc         Calculator
+	currencyCode string
+	scale        *big.Rat
+}
+
+/*
+Calculate taxes the lines, which must all be in the same currency, and
+returns an itemized breakdown.
+*/
+func (calc Calculator) Calculate(lines []Line) (*Breakdown, error) {
+
+	if len(lines) == 0 {
+		return nil, ErrNoLines
+	}
+
+	currencyCode, err := lineCurrency(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := money.LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	c := calculation{
+		calc:         calc,
+		currencyCode: currencyCode,
+		scale:        new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(def.DecimalPlaces)), nil)),
+	}
+
+	rates := calc.Jurisdiction.Rates
+
+	//line amounts in units and exact taxes
+	amounts := make([]*big.Int, len(lines))
+	exact := make([][]*big.Rat, len(lines))
+	applies := make([][]bool, len(lines))
+
+	for i, line := range lines {
+		if amounts[i], err = c.round(line.Amount.Rat(), calc.Mode); err != nil {
+			return nil, err
+		}
+
+		coefficients, total := calc.Jurisdiction.coefficients(line.Category)
+
+		net := new(big.Rat).Quo(new(big.Rat).SetInt(amounts[i]), c.scale)
+		if calc.Inclusive {
+			net.Quo(net, total.Add(total, big.NewRat(1, 1)))
+		}
+
+		exact[i] = make([]*big.Rat, len(rates))
+		applies[i] = make([]bool, len(rates))
+		for j, coefficient := range coefficients {
+			exact[i][j] = new(big.Rat).Mul(coefficient, net)
+			applies[i][j] = coefficient.Sign() != 0
+		}
+	}
+
+	//rounded taxes, in units
+	taxes := make([][]*big.Int, len(lines))
+	for i := range lines {
+		taxes[i] = make([]*big.Int, len(rates))
+	}
+
+	for j := range rates {
+		column := make([]*big.Rat, len(lines))
+		for i := range lines {
+			column[i] = exact[i][j]
+		}
+		rounded, err := c.roundColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		for i := range lines {
+			taxes[i][j] = rounded[i]
+		}
+	}
+
+	return c.breakdown(lines, amounts, taxes, applies)
+
+}
+
+// roundColumn rounds the exact amounts of one tax across the lines.
+func (c calculation) roundColumn(column []*big.Rat) ([]*big.Int, error) {
+
+	results := make([]*big.Int, len(column))
+
+	if c.calc.Rounding == RoundPerLine {
+		for i, value := range column {
+			rounded, err := c.round(value, c.calc.Mode)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = rounded
+		}
+		return results, nil
+	}
+
+	//round the total, then give each line its exact share rounded down and
+	//hand out the leftover units by largest remainder
+	sum := new(big.Rat)
+	for _, value := range column {
+		sum.Add(sum, value)
+	}
+
+	total, err := c.round(sum, c.calc.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	leftover := new(big.Int).Set(total)
+	remainders := make([]*big.Rat, len(column))
+
+	for i, value := range column {
+		if results[i], err = c.round(value, money.RoundDown); err != nil {
+			return nil, err
+		}
+		remainders[i] = new(big.Rat).Sub(new(big.Rat).Mul(value, c.scale), new(big.Rat).SetInt(results[i]))
+		leftover.Sub(leftover, results[i])
+	}
+
+	order := make([]int, len(column))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+
+	one := big.NewInt(1)
+	for i := 0; leftover.Sign() > 0; i++ {
+		idx := order[i%len(order)]
+		results[idx].Add(results[idx], one)
+		leftover.Sub(leftover, one)
+	}
+
+	return results, nil
+
+}
+
+// breakdown assembles the breakdown from line amounts and rounded taxes.
+func (c calculation) breakdown(lines []Line, amounts []*big.Int, taxes [][]*big.Int, applies [][]bool) (*Breakdown, error) {
+
+	rates := c.calc.Jurisdiction.Rates
+
+	result := &Breakdown{Lines: make([]LineTax, len(lines)), Taxes: make([]TaxTotal, len(rates))}
+
+	netTotal, taxTotal, grandTotal := new(big.Int), new(big.Int), new(big.Int)
+	taxable := make([]*big.Int, len(rates))
+	amountTotals := make([]*big.Int, len(rates))
+	for j := range rates {
+		taxable[j], amountTotals[j] = new(big.Int), new(big.Int)
+	}
+
+	var err error
+	for i, line := range lines {
+		lineTax := new(big.Int)
+		item := LineTax{Line: line, Taxes: make([]TaxAmount, len(rates))}
+
+		for j, rate := range rates {
+			lineTax.Add(lineTax, taxes[i][j])
+			amountTotals[j].Add(amountTotals[j], taxes[i][j])
+			item.Taxes[j] = TaxAmount{Name: rate.Name, Rate: rate.For(line.Category)}
+			if item.Taxes[j].Amount, err = c.currency(taxes[i][j]); err != nil {
+				return nil, err
+			}
+		}
+
+		net, total := new(big.Int).Set(amounts[i]), new(big.Int).Set(amounts[i])
+		if c.calc.Inclusive {
+			net.Sub(net, lineTax)
+		} else {
+			total.Add(total, lineTax)
+		}
+
+		for j := range rates {
+			if applies[i][j] {
+				taxable[j].Add(taxable[j], net)
+			}
+		}
+
+		netTotal.Add(netTotal, net)
+		taxTotal.Add(taxTotal, lineTax)
+		grandTotal.Add(grandTotal, total)
+
+		if item.Net, err = c.currency(net); err != nil {
+			return nil, err
+		}
+		if item.Tax, err = c.currency(lineTax); err != nil {
+			return nil, err
+		}
+		if item.Total, err = c.currency(total); err != nil {
+			return nil, err
+		}
+
+		result.Lines[i] = item
+	}
+
+	for j, rate := range rates {
+		result.Taxes[j].Name = rate.Name
+		if result.Taxes[j].Taxable, err = c.currency(taxable[j]); err != nil {
+			return nil, err
+		}
+		if result.Taxes[j].Amount, err = c.currency(amountTotals[j]); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Net, err = c.currency(netTotal); err != nil {
+		return nil, err
+	}
+	if result.Tax, err = c.currency(taxTotal); err != nil {
+		return nil, err
+	}
+	if result.Total, err = c.currency(grandTotal); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+// round rounds an exact amount of currency to a whole number of units.
+func (c calculation) round(value *big.Rat, mode money.RoundingMode) (*big.Int, error) {
+
+	rounded, err := money.FromRat(c.currencyCode, value, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).Mul(rounded.Rat(), c.scale).Num(), nil
+
+}
+
+// currency converts a number of units back into a currency.
+func (c calculation) currency(units *big.Int) (*money.Currency, error) {
+	return money.FromRat(c.currencyCode, new(big.Rat).Quo(new(big.Rat).SetInt(units), c.scale), money.RoundNearest)
+}
+
+// lineCurrency returns the lines' currency, ignoring zero amounts without one.
+func lineCurrency(lines []Line) (string, error) {
+
+	code := ""
+	for _, line := range lines {
+		if line.Amount == nil {
+			return "", fmt.Errorf("%w: line %v has no amount", money.ErrInvalidAmount, line.Reference)
+		}
+		if line.Amount.CurrencyCode == "" && line.Amount.IsZero() {
+			continue
+		}
+		lineCode := line.Amount.CurrencyCode
+		if lineCode == "" {
+			lineCode = money.CurrencyCodeDefault
+		}
+		if code == "" {
+			code = lineCode
+		} else if code != lineCode {
+			return "", fmt.Errorf("%w: %v and %v", money.ErrCurrencyMismatch, code, lineCode)
+		}
+	}
+
+	if code == "" {
+		code = money.CurrencyCodeDefault
+	}
+
+	return code, nil
+
+}
+
+/*
+Reconcile checks that the breakdown adds up: every line's taxes sum to its
+tax and its net and tax to its total, each tax total is the sum of the line
+amounts for that tax, and the order totals are the sums of the lines.
+*/
+func (breakdown *Breakdown) Reconcile() error {
+
+	net, tax, total := new(big.Rat), new(big.Rat), new(big.Rat)
+	taxTotals := make([]*big.Rat, len(breakdown.Taxes))
+	for j := range taxTotals {
+		taxTotals[j] = new(big.Rat)
+	}
+
+	for _, line := range breakdown.Lines {
+		lineTax := new(big.Rat)
+		for j, amount := range line.Taxes {
+			lineTax.Add(lineTax, amount.Amount.Rat())
+			if j < len(taxTotals) {
+				taxTotals[j].Add(taxTotals[j], amount.Amount.Rat())
+			}
+		}
+		if lineTax.Cmp(line.Tax.Rat()) != 0 {
+			return fmt.Errorf("%w: line %v taxes don't add up", ErrUnreconciled, line.Line.Reference)
+		}
+		if new(big.Rat).Add(line.Net.Rat(), line.Tax.Rat()).Cmp(line.Total.Rat()) != 0 {
+			return fmt.Errorf("%w: line %v net and tax don't add up", ErrUnreconciled, line.Line.Reference)
+		}
+		net.Add(net, line.Net.Rat())
+		tax.Add(tax, line.Tax.Rat())
+		total.Add(total, line.Total.Rat())
+	}
+
+	for j, taxTotal := range breakdown.Taxes {
+		if taxTotals[j].Cmp(taxTotal.Amount.Rat()) != 0 {
+			return fmt.Errorf("%w: %v total", ErrUnreconciled, taxTotal.Name)
+		}
+	}
+
+	if net.Cmp(breakdown.Net.Rat()) != 0 || tax.Cmp(breakdown.Tax.Rat()) != 0 || total.Cmp(breakdown.Total.Rat()) != 0 {
+		return fmt.Errorf("%w: order totals", ErrUnreconciled)
+	}
+
+	if new(big.Rat).Add(net, tax).Cmp(total) != 0 {
+		return fmt.Errorf("%w: net and tax don't add up to the total", ErrUnreconciled)
+	}
+
+	return nil
+
+}
@@ -0,0 +1,193 @@
+package tax
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/production-grid/pgrid-core/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	categoryAdmission Category = "admission"
+	categoryFood      Category = "food"
+)
+
+func usd(value string) *money.Currency {
+	return money.ParseCurrency(money.CurrencyCodeUSD, value)
+}
+
+func pct(value string) *money.BasisPoints {
+
+	bps, err := money.ParsePercentage(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return bps
+
+}
+
+func TestStackedExclusive(t *testing.T) {
+
+	assert := assert.New(t)
+
+	city := Jurisdiction{
+		Code: "US-TX-AUS",
+		Name: "Austin",
+		Rates: []Rate{
+			{Name: "State", Rate: pct("6")},
+			{Name: "City", Rate: pct("2.5"), Categories: map[Category]*money.BasisPoints{categoryAdmission: money.NewBasisPoints(0)}},
+		},
+	}
+
+	calc := Calculator{Jurisdiction: city, Mode: money.RoundNearest}
+
+	breakdown, err := calc.Calculate([]Line{
+		{Reference: "ticket", Category: categoryAdmission, Amount: usd("10.00")},
+		{Reference: "poster", Amount: usd("3.33")},
+	})
+	assert.NoError(err)
+	assert.NoError(breakdown.Reconcile())
+
+	assert.Equal("0.60", breakdown.Lines[0].Taxes[0].Amount.FormatCurrency())
+	assert.Equal("0.00", breakdown.Lines[0].Taxes[1].Amount.FormatCurrency())
+	assert.Equal("0.20", breakdown.Lines[1].Taxes[0].Amount.FormatCurrency())
+	assert.Equal("0.08", breakdown.Lines[1].Taxes[1].Amount.FormatCurrency())
+	assert.Equal("3.61", breakdown.Lines[1].Total.FormatCurrency())
+
+	assert.Equal("State", breakdown.Taxes[0].Name)
+	assert.Equal("13.33", breakdown.Taxes[0].Taxable.FormatCurrency())
+	assert.Equal("0.80", breakdown.Taxes[0].Amount.FormatCurrency())
+	assert.Equal("3.33", breakdown.Taxes[1].Taxable.FormatCurrency())
+	assert.Equal("0.08", breakdown.Taxes[1].Amount.FormatCurrency())
+
+	assert.Equal("13.33", breakdown.Net.FormatCurrency())
+	assert.Equal("0.88", breakdown.Tax.FormatCurrency())
+	assert.Equal("14.21", breakdown.Total.FormatCurrency())
+
+}
+
+func TestRoundingLevels(t *testing.T) {
+
+	assert := assert.New(t)
+
+	jurisdiction := Jurisdiction{Rates: []Rate{{Name: "Sales", Rate: pct("5")}}}
+	lines := []Line{
+		{Reference: "a", Amount: usd("1.05")},
+		{Reference: "b", Amount: usd("1.05")},
+		{Reference: "c", Amount: usd("1.05")},
+	}
+
+	perLine, err := Calculator{Jurisdiction: jurisdiction, Rounding: RoundPerLine, Mode: money.RoundNearest}.Calculate(lines)
+	assert.NoError(err)
+	assert.NoError(perLine.Reconcile())
+	assert.Equal("0.15", perLine.Tax.FormatCurrency())
+
+	perInvoice, err := Calculator{Jurisdiction: jurisdiction, Rounding: RoundPerInvoice, Mode: money.RoundNearest}.Calculate(lines)
+	assert.NoError(err)
+	assert.NoError(perInvoice.Reconcile())
+	assert.Equal("0.16", perInvoice.Tax.FormatCurrency())
+	assert.Equal("3.31", perInvoice.Total.FormatCurrency())
+
+	//the extra cent goes to the first line on a tie
+	assert.Equal("0.06", perInvoice.Lines[0].Tax.FormatCurrency())
+	assert.Equal("0.05", perInvoice.Lines[1].Tax.FormatCurrency())
+	assert.Equal("0.05", perInvoice.Lines[2].Tax.FormatCurrency())
+
+	roundedDown, err := Calculator{Jurisdiction: jurisdiction, Rounding: RoundPerInvoice, Mode: money.RoundDown}.Calculate(lines)
+	assert.NoError(err)
+	assert.NoError(roundedDown.Reconcile())
+	assert.Equal("0.15", roundedDown.Tax.FormatCurrency())
+
+	roundedUp, err := Calculator{Jurisdiction: jurisdiction, Rounding: RoundPerLine, Mode: money.RoundUp}.Calculate(lines)
+	assert.NoError(err)
+	assert.Equal("0.18", roundedUp.Tax.FormatCurrency())
+
+}
+
+func TestCompound(t *testing.T) {
+
+	assert := assert.New(t)
+
+	quebec := Jurisdiction{Code: "CA-QC", Rates: []Rate{
+		{Name: "GST", Rate: pct("5")},
+		{Name: "QST", Rate: pct("9.5"), Compound: true},
+	}}
+
+	breakdown, err := Calculator{Jurisdiction: quebec, Mode: money.RoundNearest}.Calculate([]Line{
+		{Reference: "ticket", Amount: money.ParseCurrency(money.CurrencyCodeCAD, "100.00")},
+	})
+	assert.NoError(err)
+	assert.NoError(breakdown.Reconcile())
+
+	assert.Equal(money.CurrencyCodeCAD, breakdown.Total.CurrencyCode)
+	assert.Equal("5.00", breakdown.Taxes[0].Amount.FormatCurrency())
+	assert.Equal("9.98", breakdown.Taxes[1].Amount.FormatCurrency())
+	assert.Equal("114.98", breakdown.Total.FormatCurrency())
+
+}
+
+func TestInclusive(t *testing.T) {
+
+	assert := assert.New(t)
+
+	vat := Jurisdiction{Code: "GB", Rates: []Rate{
+		{Name: "VAT", Rate: pct("20"), Categories: map[Category]*money.BasisPoints{categoryFood: money.NewBasisPoints(0)}},
+	}}
+
+	breakdown, err := Calculator{Jurisdiction: vat, Inclusive: true, Mode: money.RoundNearest}.Calculate([]Line{
+		{Reference: "ticket", Amount: usd("12.00")},
+		{Reference: "programme", Amount: usd("9.99")},
+		{Reference: "sandwich", Category: categoryFood, Amount: usd("4.50")},
+	})
+	assert.NoError(err)
+	assert.NoError(breakdown.Reconcile())
+
+	assert.Equal("10.00", breakdown.Lines[0].Net.FormatCurrency())
+	assert.Equal("2.00", breakdown.Lines[0].Tax.FormatCurrency())
+	assert.Equal("8.32", breakdown.Lines[1].Net.FormatCurrency())
+	assert.Equal("1.67", breakdown.Lines[1].Tax.FormatCurrency())
+	assert.Equal("4.50", breakdown.Lines[2].Net.FormatCurrency())
+	assert.True(breakdown.Lines[2].Tax.IsZero())
+
+	//inclusive totals are the original prices
+	assert.Equal("26.49", breakdown.Total.FormatCurrency())
+	assert.Equal("18.32", breakdown.Taxes[0].Taxable.FormatCurrency())
+
+	stacked := Jurisdiction{Rates: []Rate{{Name: "GST", Rate: pct("5")}, {Name: "PST", Rate: pct("7")}}}
+
+	breakdown, err = Calculator{Jurisdiction: stacked, Inclusive: true, Rounding: RoundPerInvoice, Mode: money.RoundNearest}.Calculate([]Line{
+		{Reference: "ticket", Amount: usd("11.20")},
+	})
+	assert.NoError(err)
+	assert.NoError(breakdown.Reconcile())
+	assert.Equal("10.00", breakdown.Net.FormatCurrency())
+	assert.Equal("0.50", breakdown.Taxes[0].Amount.FormatCurrency())
+	assert.Equal("0.70", breakdown.Taxes[1].Amount.FormatCurrency())
+
+}
+
+func TestCalculateErrors(t *testing.T) {
+
+	assert := assert.New(t)
+
+	calc := Calculator{Jurisdiction: Jurisdiction{Rates: []Rate{{Name: "Sales", Rate: pct("5")}}}}
+
+	_, err := calc.Calculate(nil)
+	assert.Equal(ErrNoLines, err)
+
+	_, err = calc.Calculate([]Line{{Amount: usd("1.00")}, {Amount: money.ParseCurrency(money.CurrencyCodeCAD, "1.00")}})
+	assert.True(errors.Is(err, money.ErrCurrencyMismatch))
+
+	_, err = calc.Calculate([]Line{{Reference: "missing"}})
+	assert.True(errors.Is(err, money.ErrInvalidAmount))
+
+	breakdown, err := calc.Calculate([]Line{{Amount: usd("10.00")}, {Amount: money.Zero()}})
+	assert.NoError(err)
+	assert.NoError(breakdown.Reconcile())
+
+	breakdown.Lines[0].Taxes[0].Amount = usd("0.49")
+	assert.True(errors.Is(breakdown.Reconcile(), ErrUnreconciled))
+
+}
@@ -0,0 +1,77 @@
+package tax
+
+import (
+	"math/big"
+
+	"github.com/production-grid/pgrid-core/pkg/money"
+)
+
+// Category is a product tax category, e.g. admission, merchandise or food.
+// Jurisdictions can tax each category at a different rate.
+type Category string
+
+// CategoryGeneral is the category for lines that don't name one.
+const CategoryGeneral Category = ""
+
+/*
+Rate is a single tax levied by a jurisdiction, such as a state sales tax or a
+GST. Rate applies to every category not listed in Categories; a category
+listed with a zero rate is exempt. A compound tax is levied on the price plus
+the taxes before it, otherwise taxes stack, each levied on the price alone.
+*/
+type Rate struct {
+	Name       string
+	Rate       *money.BasisPoints
+	Categories map[Category]*money.BasisPoints
+	Compound   bool
+}
+
+// For returns the rate for a product category.
+func (rate Rate) For(category Category) *money.BasisPoints {
+
+	if bps, ok := rate.Categories[category]; ok {
+		return bps
+	}
+
+	return rate.Rate
+
+}
+
+/*
+Jurisdiction is a place that levies taxes, such as a state, province or
+city. Rates are applied in order, which matters for compound taxes.
+*/
+type Jurisdiction struct {
+	Code  string
+	Name  string
+	Rates []Rate
+}
+
+/*
+coefficients returns, for each rate, the tax as a fraction of the net price,
+and their sum. Compound taxes apply to the net price plus the exact taxes
+before them.
+*/
+func (jurisdiction Jurisdiction) coefficients(category Category) ([]*big.Rat, *big.Rat) {
+
+	results := make([]*big.Rat, len(jurisdiction.Rates))
+	total := new(big.Rat)
+
+	for i, rate := range jurisdiction.Rates {
+		bps := ratio(rate.For(category))
+		if rate.Compound {
+			bps.Mul(bps, new(big.Rat).Add(big.NewRat(1, 1), total))
+		}
+		results[i] = bps
+		total.Add(total, bps)
+	}
+
+	return results, total
+
+}
+
+// ratio converts basis points to an exact fraction, treating nil and
+// malformed rates as zero.
+func ratio(bps *money.BasisPoints) *big.Rat {
+	return bps.Rat()
+}
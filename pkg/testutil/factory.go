@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/production-grid/pgrid-core/pkg/ids"
 	"github.com/production-grid/pgrid-core/pkg/money"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,6 +41,8 @@ func PopulateTestData(entity interface{}) interface{} {
 		case *time.Time:
 			n := time.Now()
 			val.Set(reflect.ValueOf(&n))
+		case ids.UUID:
+			val.Set(reflect.ValueOf(ids.NewUUIDv4()))
 		}
 	}
 
@@ -83,6 +86,10 @@ func AssertEquivalent(t *testing.T, actual interface{}, expected interface{}) {
 			e := fldExpected.(**time.Time)
 			a := fldActual.(**time.Time)
 			assert.True((*a).Equal(**e))
+		case *ids.UUID:
+			e := fldExpected.(*ids.UUID)
+			a := fldActual.(*ids.UUID)
+			assert.Equal(a, e)
 		}
 	}
 
@@ -0,0 +1,141 @@
+package tokens
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// minSecretLength is the shortest HMAC secret accepted, in bytes.
+const minSecretLength = 32
+
+// Key ring errors.
+var (
+	ErrInvalidKey = errors.New("invalid token signing key")
+	ErrNoKey      = errors.New("no active token signing key")
+)
+
+// KeyRing holds the HMAC keys used to sign and verify tokens. New tokens are
+// always signed with the active key, but any key on the ring can verify, so
+// keys can be rotated without invalidating tokens already issued.
+type KeyRing struct {
+	mu     sync.RWMutex
+	keys   map[string][]byte
+	active string
+	now    func() time.Time
+}
+
+// NewKeyRing returns an empty key ring.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		keys: make(map[string][]byte),
+		now:  time.Now,
+	}
+}
+
+// NewKeyRingFromHex builds a key ring from hex encoded secrets keyed by key
+// id, such as those generated by ids.NewHmacKey.
+func NewKeyRingFromHex(secrets map[string]string, active string) (*KeyRing, error) {
+
+	ring := NewKeyRing()
+
+	for id, encoded := range secrets {
+		secret, err := hex.DecodeString(encoded)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		if err := ring.Add(id, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ring.Activate(active); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+
+}
+
+// Add adds a key that can be used to verify tokens. Key ids must be between 1
+// and 255 bytes long since they're embedded in every token.
+func (ring *KeyRing) Add(id string, secret []byte) error {
+
+	if id == "" || len(id) > 255 || len(secret) < minSecretLength {
+		return ErrInvalidKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.keys[id] = append([]byte(nil), secret...)
+
+	return nil
+
+}
+
+// Activate makes the given key the one used to sign new tokens.
+func (ring *KeyRing) Activate(id string) error {
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if _, ok := ring.keys[id]; !ok {
+		return ErrNoKey
+	}
+
+	ring.active = id
+
+	return nil
+
+}
+
+// Rotate adds a key and makes it the active key.
+func (ring *KeyRing) Rotate(id string, secret []byte) error {
+
+	if err := ring.Add(id, secret); err != nil {
+		return err
+	}
+
+	return ring.Activate(id)
+
+}
+
+// Remove removes a retired key. Tokens signed with it no longer verify.
+func (ring *KeyRing) Remove(id string) {
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	delete(ring.keys, id)
+
+	if ring.active == id {
+		ring.active = ""
+	}
+
+}
+
+func (ring *KeyRing) activeKey() (string, []byte, error) {
+
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	if ring.active == "" {
+		return "", nil, ErrNoKey
+	}
+
+	return ring.active, ring.keys[ring.active], nil
+
+}
+
+func (ring *KeyRing) key(id string) ([]byte, bool) {
+
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	secret, ok := ring.keys[id]
+
+	return secret, ok
+
+}
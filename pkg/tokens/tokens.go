@@ -0,0 +1,159 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Standard token purposes.
+const (
+	PurposePasswordReset     = "password-reset"
+	PurposeEmailVerification = "email-verification"
+	PurposeTicket            = "ticket"
+)
+
+const (
+	tokenVersion   = 1
+	tokenSeparator = "."
+)
+
+// Token errors.
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Claims models the verified contents of a token.
+type Claims struct {
+	Purpose   string
+	Subject   string
+	ExpiresAt time.Time
+	Payload   []byte
+}
+
+/*
+Issue creates a compact, URL safe token for the given purpose and subject that
+expires after ttl. The purpose isn't stored in the token, but it's covered by
+the signature, so a token only verifies for the purpose it was issued for.
+*/
+func (ring *KeyRing) Issue(purpose string, subject string, ttl time.Duration, payload []byte) (string, error) {
+
+	keyID, secret, err := ring.activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	expires := ring.now().Add(ttl).Unix()
+
+	body := make([]byte, 0, 2+len(keyID)+2*binary.MaxVarintLen64+len(subject)+len(payload))
+	body = append(body, tokenVersion, byte(len(keyID)))
+	body = append(body, keyID...)
+	body = appendVarint(body, expires)
+	body = appendUvarint(body, uint64(len(subject)))
+	body = append(body, subject...)
+	body = append(body, payload...)
+
+	signature := sign(secret, purpose, body)
+
+	return encoding.EncodeToString(body) + tokenSeparator + encoding.EncodeToString(signature), nil
+
+}
+
+/*
+Verify checks a token's signature against the expected purpose and its expiry,
+and returns its claims. The signature is compared in constant time.
+*/
+func (ring *KeyRing) Verify(token string, purpose string) (*Claims, error) {
+
+	sep := strings.Index(token, tokenSeparator)
+	if sep < 0 {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := encoding.DecodeString(token[:sep])
+	if err != nil || len(body) < 2 || body[0] != tokenVersion {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := encoding.DecodeString(token[sep+1:])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	keyLen := int(body[1])
+	if len(body) < 2+keyLen {
+		return nil, ErrInvalidToken
+	}
+
+	secret, ok := ring.key(string(body[2 : 2+keyLen]))
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal(signature, sign(secret, purpose, body)) {
+		return nil, ErrInvalidToken
+	}
+
+	rest := body[2+keyLen:]
+
+	expires, n := binary.Varint(rest)
+	if n <= 0 {
+		return nil, ErrInvalidToken
+	}
+	rest = rest[n:]
+
+	subjectLen, n := binary.Uvarint(rest)
+	if n <= 0 || uint64(len(rest)-n) < subjectLen {
+		return nil, ErrInvalidToken
+	}
+	rest = rest[n:]
+
+	claims := &Claims{
+		Purpose:   purpose,
+		Subject:   string(rest[:subjectLen]),
+		ExpiresAt: time.Unix(expires, 0),
+	}
+
+	if payload := rest[subjectLen:]; len(payload) > 0 {
+		claims.Payload = payload
+	}
+
+	if !ring.now().Before(claims.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	return claims, nil
+
+}
+
+// sign computes the HMAC of the purpose and token body. The purpose is length
+// prefixed so it can't run into the body.
+func sign(secret []byte, purpose string, body []byte) []byte {
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(appendUvarint(nil, uint64(len(purpose))))
+	mac.Write([]byte(purpose))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+
+}
+
+func appendVarint(buf []byte, value int64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(scratch[:], value)
+	return append(buf, scratch[:n]...)
+}
+
+func appendUvarint(buf []byte, value uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], value)
+	return append(buf, scratch[:n]...)
+}
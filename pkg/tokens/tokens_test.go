@@ -0,0 +1,133 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/production-grid/pgrid-core/pkg/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRing(t *testing.T) *KeyRing {
+
+	ring, err := NewKeyRingFromHex(map[string]string{"k1": ids.NewHmacKey()}, "k1")
+	assert.NoError(t, err)
+
+	return ring
+
+}
+
+func TestIssueAndVerify(t *testing.T) {
+
+	assert := assert.New(t)
+
+	ring := newTestRing(t)
+
+	token, err := ring.Issue(PurposePasswordReset, "user-123", time.Hour, []byte("nonce"))
+	assert.NoError(err)
+	assert.NotContains(token, "=")
+	assert.NotContains(token, "+")
+	assert.NotContains(token, "/")
+
+	claims, err := ring.Verify(token, PurposePasswordReset)
+	assert.NoError(err)
+	assert.Equal("user-123", claims.Subject)
+	assert.Equal(PurposePasswordReset, claims.Purpose)
+	assert.Equal([]byte("nonce"), claims.Payload)
+
+	_, err = ring.Verify(token, PurposeEmailVerification)
+	assert.Equal(ErrInvalidToken, err)
+
+}
+
+func TestExpiry(t *testing.T) {
+
+	assert := assert.New(t)
+
+	ring := newTestRing(t)
+
+	now := time.Now()
+	ring.now = func() time.Time { return now }
+
+	token, err := ring.Issue(PurposeTicket, "ticket-1", time.Minute, nil)
+	assert.NoError(err)
+
+	ring.now = func() time.Time { return now.Add(2 * time.Minute) }
+
+	_, err = ring.Verify(token, PurposeTicket)
+	assert.Equal(ErrTokenExpired, err)
+
+}
+
+func TestTampering(t *testing.T) {
+
+	assert := assert.New(t)
+
+	ring := newTestRing(t)
+
+	token, err := ring.Issue(PurposeTicket, "ticket-1", time.Hour, nil)
+	assert.NoError(err)
+
+	other, err := ring.Issue(PurposeTicket, "ticket-2", time.Hour, nil)
+	assert.NoError(err)
+
+	//splice the body of one token onto the signature of another
+	forged := token[:len(token)/2] + other[len(other)/2:]
+
+	for _, candidate := range []string{forged, token + "x", "", "garbage", token[:10]} {
+		_, err = ring.Verify(candidate, PurposeTicket)
+		assert.Error(err, candidate)
+	}
+
+}
+
+func TestKeyRotation(t *testing.T) {
+
+	assert := assert.New(t)
+
+	ring := newTestRing(t)
+
+	oldToken, err := ring.Issue(PurposeTicket, "ticket-1", time.Hour, nil)
+	assert.NoError(err)
+
+	assert.NoError(ring.Rotate("k2", ids.RandomBytes(32)))
+
+	newToken, err := ring.Issue(PurposeTicket, "ticket-2", time.Hour, nil)
+	assert.NoError(err)
+
+	_, err = ring.Verify(oldToken, PurposeTicket)
+	assert.NoError(err)
+
+	_, err = ring.Verify(newToken, PurposeTicket)
+	assert.NoError(err)
+
+	ring.Remove("k1")
+
+	_, err = ring.Verify(oldToken, PurposeTicket)
+	assert.Equal(ErrInvalidToken, err)
+
+	assert.Equal(ErrInvalidKey, ring.Add("short", []byte("secret")))
+
+}
+
+func TestIssueForID(t *testing.T) {
+
+	assert := assert.New(t)
+
+	ring := newTestRing(t)
+	tickets := ids.NewObfuscator("secret", "ticket", 8)
+	users := ids.NewObfuscator("secret", "user", 8)
+
+	token, err := ring.IssueForID(PurposeTicket, tickets, 1234, time.Hour, nil)
+	assert.NoError(err)
+
+	id, claims, err := ring.VerifyID(token, PurposeTicket, tickets)
+	assert.NoError(err)
+	assert.Equal(int64(1234), id)
+	assert.Equal(PurposeTicket, claims.Purpose)
+	assert.NotContains(claims.Subject, "1234")
+
+	_, _, err = ring.VerifyID(token, PurposeTicket, users)
+	assert.Equal(ErrInvalidToken, err)
+
+}